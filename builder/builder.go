@@ -0,0 +1,255 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/nested"
+	"github.com/andrey-viktorov/jsonfilter-go/serde"
+)
+
+// Node is an operator tree under construction. A Node whose operator, or any operator below it,
+// could not be constructed carries the errors instead, and Build reports them all.
+type Node struct {
+	op         jsonfilter.Operator
+	complexity int
+	err        error
+}
+
+var errEmptyNode = errors.New("filter node is empty")
+
+// Build validates the tree and enforces the default complexity limit of serde.DefaultParser.
+func (n Node) Build() (jsonfilter.Operator, error) {
+	return n.BuildWith(serde.DefaultParser())
+}
+
+// BuildWith validates the tree and enforces the complexity limit of parser, so that a built tree
+// is accepted exactly when the equivalent filter document would be. The payload policy of parser
+// applies to the built tree as well.
+func (n Node) BuildWith(parser serde.Parser) (jsonfilter.Operator, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	if n.op == nil {
+		return nil, errEmptyNode
+	}
+	if limit := parser.MaxComplexity(); n.complexity > limit {
+		return nil, fmt.Errorf("filter complexity %d exceeds limit %d", n.complexity, limit)
+	}
+	if v := n.op.Validate(); !v.Valid {
+		return nil, fmt.Errorf("operator %s is invalid: %s", n.op.Name(), v.CauseDescription)
+	}
+	return jsonfilter.WithPayloadValidation(n.op, parser.PayloadPolicy()), nil
+}
+
+// MustBuild is like Build but panics on error.
+func (n Node) MustBuild() jsonfilter.Operator {
+	op, err := n.Build()
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Err returns the construction errors collected in the tree, if any.
+func (n Node) Err() error {
+	return n.err
+}
+
+// Complexity returns the complexity of the tree as counted by serde.Parser.
+func (n Node) Complexity() int {
+	return n.complexity
+}
+
+// Wrap turns an existing operator tree into a Node whose complexity is that of the whole tree, see
+// serde.Complexity.
+func Wrap(op jsonfilter.Operator) Node {
+	if op == nil {
+		return Node{err: errEmptyNode}
+	}
+	return Node{op: op, complexity: serde.Complexity(op)}
+}
+
+// Compare builds any comparison operator from its type and the value a filter document would
+// carry, for example Compare(comparison.Size, "$.items", map[string]interface{}{"max": 3}).
+func Compare(t comparison.Type, field string, value interface{}) Node {
+	op, err := comparison.Instantiate(t, serde.FieldPath(field), value)
+	if err != nil {
+		return Node{err: fmt.Errorf("%s %s: %w", t, field, err)}
+	}
+	return Node{op: op, complexity: 1}
+}
+
+// Eq matches when the value at field equals value.
+func Eq(field string, value interface{}) Node { return Compare(comparison.Equal, field, value) }
+
+// Ne matches when the value at field differs from value.
+func Ne(field string, value interface{}) Node { return Compare(comparison.NotEqual, field, value) }
+
+// Rx matches when the string at field matches the regular expression pattern.
+func Rx(field, pattern string) Node { return Compare(comparison.Regex, field, pattern) }
+
+// Lt matches when the value at field is less than value.
+func Lt(field string, value interface{}) Node { return Compare(comparison.LessThan, field, value) }
+
+// Le matches when the value at field is less than or equal to value.
+func Le(field string, value interface{}) Node { return Compare(comparison.LessEqual, field, value) }
+
+// Gt matches when the value at field is greater than value.
+func Gt(field string, value interface{}) Node { return Compare(comparison.GreaterThan, field, value) }
+
+// Ge matches when the value at field is greater than or equal to value.
+func Ge(field string, value interface{}) Node { return Compare(comparison.GreaterEqual, field, value) }
+
+// In matches when the value at field, or one of its elements, equals one of values.
+func In(field string, values ...interface{}) Node { return Compare(comparison.In, field, values) }
+
+// NotIn matches when neither the value at field nor any of its elements equals one of values.
+func NotIn(field string, values ...interface{}) Node { return Compare(comparison.NotIn, field, values) }
+
+// Contains matches when the string at field contains value or the array at field has an element
+// equal to it.
+func Contains(field string, value interface{}) Node {
+	return Compare(comparison.Contains, field, value)
+}
+
+// NotContains is the negation of Contains.
+func NotContains(field string, value interface{}) Node {
+	return Compare(comparison.NotContains, field, value)
+}
+
+// Exists matches when field is present, or absent when want is false.
+func Exists(field string, want bool) Node { return Compare(comparison.Exists, field, want) }
+
+// Type matches when the value at field has one of the JSON types, such as comparison.TypeString.
+func Type(field string, types ...string) Node { return Compare(comparison.JSONType, field, types) }
+
+// And matches when every child matches.
+func And(children ...Node) Node { return combine(logic.And, children) }
+
+// Or matches when at least one child matches.
+func Or(children ...Node) Node { return combine(logic.Or, children) }
+
+// Xor matches when exactly one child matches.
+func Xor(children ...Node) Node { return combine(logic.Xor, children) }
+
+// Nand matches when at least one child does not match.
+func Nand(children ...Node) Node { return combine(logic.Nand, children) }
+
+// Nor matches when no child matches.
+func Nor(children ...Node) Node { return combine(logic.Nor, children) }
+
+// Not matches when child does not match.
+func Not(child Node) Node { return combine(logic.Nor, []Node{child}) }
+
+// AtLeast matches when count or more children match.
+func AtLeast(count int, children ...Node) Node { return threshold(logic.AtLeast, count, children) }
+
+// AtMost matches when count or fewer children match.
+func AtMost(count int, children ...Node) Node { return threshold(logic.AtMost, count, children) }
+
+// Exactly matches when exactly count children match.
+func Exactly(count int, children ...Node) Node { return threshold(logic.Exactly, count, children) }
+
+// If evaluates then when condition matches and matches otherwise.
+func If(condition, then Node) Node {
+	return conditional(condition, then, nil)
+}
+
+// IfElse evaluates then when condition matches and otherwise instead.
+func IfElse(condition, then, otherwise Node) Node {
+	return conditional(condition, then, &otherwise)
+}
+
+// Nested evaluates child against the document embedded in the string at field.
+func Nested(field string, decoding nested.Decoding, child Node) Node {
+	return wrapChild(fmt.Sprintf("%s %s", nested.OperatorName, field), child, func(op jsonfilter.Operator) (jsonfilter.Operator, error) {
+		return nested.NewOperator(serde.FieldPath(field), decoding, op)
+	})
+}
+
+// ElemMatch matches when child matches at least one element of the array at field.
+func ElemMatch(field string, child Node) Node {
+	return wrapChild(fmt.Sprintf("%s %s", nested.ElemMatchName, field), child, func(op jsonfilter.Operator) (jsonfilter.Operator, error) {
+		return nested.NewElemMatchOperator(serde.FieldPath(field), op)
+	})
+}
+
+// collect returns the operators of nodes and their total complexity, or the joined errors of every
+// node that failed, labelled with where they occurred.
+func collect(where string, nodes []Node) ([]jsonfilter.Operator, int, error) {
+	ops := make([]jsonfilter.Operator, 0, len(nodes))
+	complexity := 0
+	var errs []error
+	for i, node := range nodes {
+		switch {
+		case node.err != nil:
+			errs = append(errs, node.err)
+		case node.op == nil:
+			errs = append(errs, fmt.Errorf("%s child %d: %w", where, i, errEmptyNode))
+		default:
+			ops = append(ops, node.op)
+			complexity += node.complexity
+		}
+	}
+	return ops, complexity, errors.Join(errs...)
+}
+
+func combine(typ logic.Type, children []Node) Node {
+	ops, complexity, err := collect(string(typ), children)
+	if err != nil {
+		return Node{err: err}
+	}
+	op, err := logic.NewOperator(typ, ops)
+	if err != nil {
+		return Node{err: err}
+	}
+	return Node{op: op, complexity: 1 + complexity}
+}
+
+func threshold(typ logic.Type, count int, children []Node) Node {
+	ops, complexity, err := collect(string(typ), children)
+	if err != nil {
+		return Node{err: err}
+	}
+	op, err := logic.NewThresholdOperator(typ, count, ops)
+	if err != nil {
+		return Node{err: err}
+	}
+	return Node{op: op, complexity: 1 + complexity}
+}
+
+func conditional(condition, then Node, otherwise *Node) Node {
+	nodes := []Node{condition, then}
+	if otherwise != nil {
+		nodes = append(nodes, *otherwise)
+	}
+	ops, complexity, err := collect(string(logic.If), nodes)
+	if err != nil {
+		return Node{err: err}
+	}
+	var elseOp jsonfilter.Operator
+	if len(ops) == 3 {
+		elseOp = ops[2]
+	}
+	op, err := logic.NewConditionalOperator(ops[0], ops[1], elseOp)
+	if err != nil {
+		return Node{err: err}
+	}
+	return Node{op: op, complexity: 1 + complexity}
+}
+
+func wrapChild(where string, child Node, build func(jsonfilter.Operator) (jsonfilter.Operator, error)) Node {
+	ops, complexity, err := collect(where, []Node{child})
+	if err != nil {
+		return Node{err: err}
+	}
+	op, err := build(ops[0])
+	if err != nil {
+		return Node{err: fmt.Errorf("%s: %w", where, err)}
+	}
+	return Node{op: op, complexity: 1 + complexity}
+}
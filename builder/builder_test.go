@@ -0,0 +1,112 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/nested"
+	"github.com/andrey-viktorov/jsonfilter-go/serde"
+)
+
+func TestBuildMatchesParsedFilter(t *testing.T) {
+	built, err := And(
+		Eq("state", "done"),
+		Or(Rx("id", "^ABC"), Gt("amount", 100)),
+		Not(In("tags", "spam", "test")),
+		ElemMatch("items", And(Eq("sku", "A1"), Ge("qty", 2))),
+		IfElse(Exists("coupon", true), Type("coupon", comparison.TypeString), Le("amount", 1000)),
+		Nested("body", nested.JSON, Contains("roles", "admin")),
+	).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text, err := serde.ToText(built)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed, err := serde.DefaultParser().FromText(text)
+	if err != nil {
+		t.Fatalf("expected printed tree to parse back: %v", err)
+	}
+
+	for _, payload := range []string{
+		`{"state":"done","id":"ABC-1","items":[{"sku":"A1","qty":3}],"amount":5,"body":"{\"roles\":[\"admin\"]}"}`,
+		`{"state":"done","id":"X","amount":500,"tags":["spam"],"items":[{"sku":"A1","qty":3}],"body":"{\"roles\":[\"admin\"]}"}`,
+		`{"state":"done","id":"ABC","coupon":5,"items":[{"sku":"A1","qty":2}],"body":"{\"roles\":[\"admin\"]}"}`,
+		`{"state":"done","id":"ABC","coupon":"X","items":[{"sku":"A1","qty":2}],"body":"{\"roles\":[\"user\"]}"}`,
+	} {
+		want := parsed.Evaluate([]byte(payload)).Match
+		if got := built.Evaluate([]byte(payload)).Match; got != want {
+			t.Fatalf("%s: built tree returned %v, parsed tree %v", payload, got, want)
+		}
+	}
+	if res := built.Evaluate([]byte(`{"state":"done","id":"ABC-1","items":[{"sku":"A1","qty":3}],"amount":5,"body":"{\"roles\":[\"admin\"]}"}`)); !res.Match {
+		t.Fatalf("expected match: %#v", res)
+	}
+}
+
+func TestBuildCollectsErrors(t *testing.T) {
+	_, err := And(
+		Rx("id", "("),
+		Or(Lt("amount", true), Eq("", 1)),
+		AtLeast(3, Eq("a", 1)),
+		Node{},
+	).Build()
+	if err == nil {
+		t.Fatalf("expected construction errors")
+	}
+	for _, want := range []string{"invalid regex pattern", "lt amount", "json path must not be empty", "count 3", "and child 3: filter node is empty"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+	if _, err := (Node{}).Build(); err == nil {
+		t.Fatalf("expected empty node to be rejected")
+	}
+}
+
+func TestBuildEnforcesComplexityLimit(t *testing.T) {
+	node := And(Eq("a", 1), Eq("b", 2), If(Eq("c", 3), Eq("d", 4)))
+	if node.Complexity() != 6 {
+		t.Fatalf("expected complexity 6, got %d", node.Complexity())
+	}
+	if _, err := node.BuildWith(serde.NewParser(5)); err == nil || !strings.Contains(err.Error(), "filter complexity 6 exceeds limit 5") {
+		t.Fatalf("expected complexity error, got %v", err)
+	}
+	if _, err := node.BuildWith(serde.NewParser(6)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWrapCountsWholeTree(t *testing.T) {
+	op, err := serde.DefaultParser().FromText(`$.a == 1 && $.b == 2 && $.c == 3 && $.d == 4 && $.e == 5`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	node := Wrap(op)
+	if node.Complexity() != 6 {
+		t.Fatalf("expected complexity 6, got %d", node.Complexity())
+	}
+	if _, err := node.BuildWith(serde.NewParser(2)); err == nil {
+		t.Fatalf("expected wrapped tree to be held to the complexity limit")
+	}
+	if Or(node, Eq("f", 6)).Complexity() != 8 {
+		t.Fatalf("expected parents to count the wrapped tree")
+	}
+}
+
+func TestFieldsAcceptTextPaths(t *testing.T) {
+	op, err := And(
+		Eq("$.a", "x"),
+		ElemMatch("$.items", Eq("$.sku", "A1")),
+		Nested("$.raw", nested.JSON, Type("$", comparison.TypeObject)),
+	).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"a":"x","items":[{"sku":"A1"}],"raw":"{}"}`)); !res.Match {
+		t.Fatalf("expected $. paths to address the payload: %#v", res)
+	}
+}
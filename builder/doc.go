@@ -0,0 +1,5 @@
+// Package builder constructs operator trees in Go without filter documents or
+// maps. Constructors such as Eq, And and ElemMatch return Nodes that collect
+// construction errors instead of panicking; Build reports them together,
+// validates the tree and applies the same complexity guard as serde.Parser.
+package builder
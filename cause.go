@@ -0,0 +1,69 @@
+package jsonfilter
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// CauseCode classifies why an operator did not match. Codes are stable and meant for programs;
+// CauseDescription remains the text for people.
+type CauseCode string
+
+const (
+	// CausePathNotFound reports that the path resolved to no value.
+	CausePathNotFound CauseCode = "PathNotFound"
+	// CauseTypeMismatch reports that the value has a JSON type the operator cannot compare.
+	CauseTypeMismatch CauseCode = "TypeMismatch"
+	// CauseMismatch reports that the value was compared and does not satisfy the operator.
+	CauseMismatch CauseCode = "Mismatch"
+	// CauseDecodeFailed reports that a nested operator could not decode the embedded document.
+	CauseDecodeFailed CauseCode = "DecodeFailed"
+	// CauseEvaluationError reports that the operator could not be evaluated, see
+	// EvaluationResult.Error.
+	CauseEvaluationError CauseCode = "EvaluationError"
+	// CauseInvalidPayload reports that the document named by Path, such as @body, is not valid
+	// JSON, see WithPayloadValidation.
+	CauseInvalidPayload CauseCode = "InvalidPayload"
+	// CauseChildMismatch reports that the results of the child operators, listed in
+	// ChildOperators, did not combine into a match.
+	CauseChildMismatch CauseCode = "ChildMismatch"
+)
+
+// Cause is the machine-readable form of CauseDescription. It is only filled in by explaining
+// evaluations, see Explain, so that ordinary evaluation does not pay for it.
+type Cause struct {
+	Code CauseCode `json:"code" yaml:"code"`
+	// Path is the path of the operator as written in the filter.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Expected is the literal the operator compares with.
+	Expected interface{} `json:"expected,omitempty" yaml:"expected,omitempty"`
+	// Actual is the value found in the payload, decoded with numbers kept as json.Number.
+	Actual interface{} `json:"actual,omitempty" yaml:"actual,omitempty"`
+}
+
+// Explain evaluates op against payload with structured causes: every result that does not match
+// carries a Cause, and logic operators evaluate all of their children and list their results.
+func Explain(op Operator, payload []byte) EvaluationResult {
+	return EvaluateContext(op, NewContext(payload).WithExplain())
+}
+
+// NewCause builds a Cause from the raw JSON of the actual value, which may be empty when the path
+// resolved to nothing.
+func NewCause(code CauseCode, path string, expected interface{}, actualRaw string) *Cause {
+	return &Cause{Code: code, Path: path, Expected: expected, Actual: DecodeRaw(actualRaw)}
+}
+
+// DecodeRaw decodes the raw JSON of a value found in a payload, keeping numbers as json.Number. It
+// returns nil for an empty string and raw itself when raw is not valid JSON.
+func DecodeRaw(raw string) interface{} {
+	if raw == "" {
+		return nil
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return raw
+	}
+	return value
+}
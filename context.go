@@ -0,0 +1,164 @@
+package jsonfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BodyDocument is the name under which a Context exposes its payload. Paths without a document prefix
+// address the body.
+const BodyDocument = "body"
+
+// Context is a set of named JSON documents evaluated together, such as the request body plus its
+// headers, query parameters or tenant configuration. Paths address a document with an `@name.` prefix,
+// e.g. `@headers.x-tenant`; all other paths are resolved against the body.
+//
+// A Context is not safe for concurrent modification but may be shared by concurrent evaluations once
+// it is built.
+type Context struct {
+	body        []byte
+	names       []string
+	docs        [][]byte
+	explain     bool
+	propagation ErrorPropagation
+}
+
+// ErrorPropagation selects how logic operators combine child results that are evaluation errors
+// (see EvaluationResult.Error).
+type ErrorPropagation int
+
+const (
+	// ErrorsAsNoMatch counts an errored child as a child that did not match, so logic operators
+	// always reach a verdict. This is the behaviour of Evaluate.
+	ErrorsAsNoMatch ErrorPropagation = iota
+	// ErrorsAsUnknown treats an errored child as unknown, as SQL does with NULL: the parent still
+	// decides when the remaining children settle the verdict either way, e.g. and with a
+	// non-matching child, and is an error otherwise.
+	ErrorsAsUnknown
+	// ErrorsFail makes any errored child an error of its parent.
+	ErrorsFail
+)
+
+// ContextOperator is implemented by operators that can resolve paths against a Context.
+type ContextOperator interface {
+	Operator
+	// EvaluateContext runs the operator against the documents of ctx.
+	EvaluateContext(ctx *Context) EvaluationResult
+}
+
+// NewContext returns a context whose body is the given payload.
+func NewContext(body []byte) *Context {
+	return &Context{body: body}
+}
+
+// With adds or replaces the named document and returns the context for chaining. The document
+// lists are copied rather than written in place, because contexts derived with WithBody share them.
+func (c *Context) With(name string, doc []byte) *Context {
+	if name == BodyDocument {
+		c.body = doc
+		return c
+	}
+	for i, existing := range c.names {
+		if existing == name {
+			docs := make([][]byte, len(c.docs))
+			copy(docs, c.docs)
+			docs[i] = doc
+			c.docs = docs
+			return c
+		}
+	}
+	c.names = append(c.names[:len(c.names):len(c.names)], name)
+	c.docs = append(c.docs[:len(c.docs):len(c.docs)], doc)
+	return c
+}
+
+// WithValue marshals value to JSON and adds it as the named document.
+func (c *Context) WithValue(name string, value interface{}) (*Context, error) {
+	doc, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("marshal context document %s: %w", name, err)
+	}
+	return c.With(name, doc), nil
+}
+
+// WithBody returns a copy of the context whose body is replaced and whose other documents are shared.
+// Later calls to With on either context do not affect the other.
+func (c *Context) WithBody(body []byte) *Context {
+	copied := *c
+	copied.body = body
+	return &copied
+}
+
+// WithExplain makes evaluations against the context fill in Cause on every result that does not
+// match and returns the context for chaining.
+func (c *Context) WithExplain() *Context {
+	c.explain = true
+	return c
+}
+
+// Explaining reports whether evaluations against the context fill in Cause. It is false for a nil
+// context.
+func (c *Context) Explaining() bool {
+	return c != nil && c.explain
+}
+
+// WithErrorPropagation selects how logic operators combine errored children and returns the
+// context for chaining.
+func (c *Context) WithErrorPropagation(propagation ErrorPropagation) *Context {
+	c.propagation = propagation
+	return c
+}
+
+// ErrorPropagation returns how logic operators combine errored children. It is ErrorsAsNoMatch for
+// a nil context.
+func (c *Context) ErrorPropagation() ErrorPropagation {
+	if c == nil {
+		return ErrorsAsNoMatch
+	}
+	return c.propagation
+}
+
+// Body returns the payload of the context.
+func (c *Context) Body() []byte {
+	return c.body
+}
+
+// Document returns the named document.
+func (c *Context) Document(name string) ([]byte, bool) {
+	if name == BodyDocument {
+		return c.body, true
+	}
+	for i, existing := range c.names {
+		if existing == name {
+			return c.docs[i], true
+		}
+	}
+	return nil, false
+}
+
+// Resolve splits a path into the document it addresses and the path within that document. `@name`
+// prefixes that do not name a document are left to gjson, whose modifiers also start with `@`.
+func (c *Context) Resolve(path string) ([]byte, string) {
+	if len(path) < 2 || path[0] != '@' {
+		return c.body, path
+	}
+	name, rest, hasRest := strings.Cut(path[1:], ".")
+	doc, ok := c.Document(name)
+	if !ok {
+		return c.body, path
+	}
+	if !hasRest {
+		return doc, "@this"
+	}
+	return doc, rest
+}
+
+// EvaluateContext evaluates op against the documents of ctx. Operators that do not implement
+// ContextOperator are evaluated against the body.
+func EvaluateContext(op Operator, ctx *Context) EvaluationResult {
+	if c, ok := op.(ContextOperator); ok {
+		return c.EvaluateContext(ctx)
+	}
+	return op.Evaluate(ctx.body)
+}
@@ -6,6 +6,47 @@ type EvaluationResult struct {
 	OperatorName     string             `json:"operatorName" yaml:"operatorName"`
 	CauseDescription string             `json:"causeDescription,omitempty" yaml:"causeDescription,omitempty"`
 	ChildOperators   []EvaluationResult `json:"childOperators,omitempty" yaml:"childOperators,omitempty"`
+	// Error reports that the operator could not decide whether the payload matches, for example
+	// because an embedded document could not be decoded. Match is always false when Error is set.
+	Error bool `json:"error,omitempty" yaml:"error,omitempty"`
+	// Cause is set on results that do not match when the evaluation explains itself, see Explain.
+	Cause *Cause `json:"cause,omitempty" yaml:"cause,omitempty"`
+}
+
+// Outcome is the tri-state verdict of an evaluation.
+type Outcome int
+
+const (
+	// OutcomeNoMatch is a definitive non-match: the payload was evaluated and does not satisfy the filter.
+	OutcomeNoMatch Outcome = iota
+	// OutcomeMatch means the payload satisfies the filter.
+	OutcomeMatch
+	// OutcomeError means the evaluation failed and no verdict could be reached.
+	OutcomeError
+)
+
+// String returns the name of the outcome.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeMatch:
+		return "match"
+	case OutcomeError:
+		return "error"
+	default:
+		return "no match"
+	}
+}
+
+// Outcome returns the tri-state verdict of the result.
+func (r EvaluationResult) Outcome() Outcome {
+	switch {
+	case r.Error:
+		return OutcomeError
+	case r.Match:
+		return OutcomeMatch
+	default:
+		return OutcomeNoMatch
+	}
 }
 
 // ValidResult returns a successful EvaluationResult for the supplied operator name.
@@ -18,6 +59,12 @@ func ErrorResult(operatorName, cause string) EvaluationResult {
 	return EvaluationResult{Match: false, OperatorName: operatorName, CauseDescription: cause}
 }
 
+// EvaluationErrorResult returns an EvaluationResult for an evaluation that failed without reaching a
+// verdict, as opposed to ErrorResult, which reports a definitive non-match.
+func EvaluationErrorResult(operatorName, cause string) EvaluationResult {
+	return EvaluationResult{Match: false, OperatorName: operatorName, CauseDescription: cause, Error: true}
+}
+
 // AggregateResult aggregates child operator results using a precomputed match value.
 func AggregateResult(operatorName string, match bool, children []EvaluationResult, cause string) EvaluationResult {
 	return EvaluationResult{
@@ -4,10 +4,9 @@ go 1.25.4
 
 require (
 	github.com/tidwall/gjson v1.18.0
+	github.com/tidwall/match v1.1.1
+	golang.org/x/text v0.33.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-require (
-	github.com/tidwall/match v1.1.1 // indirect
-	github.com/tidwall/pretty v1.2.0 // indirect
-)
+require github.com/tidwall/pretty v1.2.0 // indirect
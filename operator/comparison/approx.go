@@ -0,0 +1,154 @@
+package comparison
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// DefaultApproxEpsilon is the absolute tolerance used when neither tolerance is configured.
+const DefaultApproxEpsilon = 1e-9
+
+// ApproxTolerance configures how far ApproxOperator lets values drift. A value matches when it is
+// within Absolute of the target, or within Relative times the larger magnitude of the two.
+type ApproxTolerance struct {
+	Absolute float64
+	Relative float64
+}
+
+// ApproxOperator compares a floating point value at a JSON path with a tolerance, e.g. so that
+// 0.1+0.2 matches 0.3.
+type ApproxOperator struct {
+	jsonPath        string
+	expected        float64
+	tolerance       ApproxTolerance
+	pathNotFoundMsg string
+	notNumberMsg    string
+	mismatchMsg     string
+}
+
+// NewApproxOperator constructs an ApproxOperator. A zero tolerance selects DefaultApproxEpsilon.
+func NewApproxOperator(jsonPath string, expected float64, tolerance ApproxTolerance) (*ApproxOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if math.IsNaN(expected) || math.IsInf(expected, 0) {
+		return nil, fmt.Errorf("approx operator expects a finite value")
+	}
+	if tolerance.Absolute < 0 || tolerance.Relative < 0 {
+		return nil, fmt.Errorf("approx tolerance must not be negative")
+	}
+	if tolerance.Absolute == 0 && tolerance.Relative == 0 {
+		tolerance.Absolute = DefaultApproxEpsilon
+	}
+	return &ApproxOperator{
+		jsonPath:        jsonPath,
+		expected:        expected,
+		tolerance:       tolerance,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		notNumberMsg:    "value at json path " + jsonPath + " is not a number",
+		mismatchMsg:     "value is not approximately " + strconv.FormatFloat(expected, 'g', -1, 64),
+	}, nil
+}
+
+// MustNewApproxOperator panics when inputs are invalid.
+func MustNewApproxOperator(jsonPath string, expected float64, tolerance ApproxTolerance) *ApproxOperator {
+	op, err := NewApproxOperator(jsonPath, expected, tolerance)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *ApproxOperator) Name() string {
+	return string(Approx)
+}
+
+// Spec describes the operator.
+func (o *ApproxOperator) Spec() Spec {
+	if o.tolerance == (ApproxTolerance{Absolute: DefaultApproxEpsilon}) {
+		return Spec{Type: Approx, Field: o.jsonPath, Value: o.expected}
+	}
+	cfg := map[string]interface{}{"value": o.expected}
+	if o.tolerance.Absolute != 0 {
+		cfg["abs"] = o.tolerance.Absolute
+	}
+	if o.tolerance.Relative != 0 {
+		cfg["rel"] = o.tolerance.Relative
+	}
+	return Spec{Type: Approx, Field: o.jsonPath, Value: cfg}
+}
+
+// Evaluate fetches the number at jsonPath and checks it is within tolerance of the expected value.
+func (o *ApproxOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *ApproxOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *ApproxOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+	if actual.Type != gjson.Number {
+		return jsonfilter.ErrorResult(o.Name(), o.notNumberMsg)
+	}
+
+	diff := math.Abs(actual.Num - o.expected)
+	scale := math.Max(math.Abs(actual.Num), math.Abs(o.expected))
+	if diff <= o.tolerance.Absolute || diff <= o.tolerance.Relative*scale {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *ApproxOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if o.tolerance.Absolute < 0 || o.tolerance.Relative < 0 {
+		return jsonfilter.ErrorValidationResult(o.Name(), "approx tolerance must not be negative")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// parseApprox accepts either the target number or an object with value, abs and rel.
+func parseApprox(value interface{}) (float64, ApproxTolerance, error) {
+	cfg, ok := toStringMap(value)
+	if !ok {
+		cfg = map[string]interface{}{"value": value}
+	}
+
+	var tolerance ApproxTolerance
+	var expected float64
+	hasValue := false
+	for key, raw := range cfg {
+		f, ok := toFloat(raw)
+		if !ok {
+			return 0, ApproxTolerance{}, fmt.Errorf("approx option %s expects a number, got %v", key, raw)
+		}
+		switch key {
+		case "value":
+			expected, hasValue = f, true
+		case "abs":
+			tolerance.Absolute = f
+		case "rel":
+			tolerance.Relative = f
+		default:
+			return 0, ApproxTolerance{}, fmt.Errorf("approx operator does not support option %q", key)
+		}
+	}
+	if !hasValue {
+		return 0, ApproxTolerance{}, fmt.Errorf("approx operator requires value")
+	}
+	return expected, tolerance, nil
+}
@@ -0,0 +1,173 @@
+package comparison
+
+import (
+	"fmt"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// BetweenBounds configures the interval accepted by BetweenOperator. Bounds are numeric literals
+// (numbers, json.Number or numeric strings) compared as exact decimals; bounds are inclusive unless
+// marked exclusive.
+type BetweenBounds struct {
+	Min          interface{}
+	Max          interface{}
+	ExclusiveMin bool
+	ExclusiveMax bool
+}
+
+// BetweenOperator checks that the number at a JSON path lies within an interval.
+type BetweenOperator struct {
+	jsonPath        string
+	bounds          BetweenBounds
+	min, max        decimal
+	pathNotFoundMsg string
+	notNumberMsg    string
+	mismatchMsg     string
+}
+
+// NewBetweenOperator constructs a BetweenOperator, parsing both bounds once.
+func NewBetweenOperator(jsonPath string, bounds BetweenBounds) (*BetweenOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	minText, ok := numberLiteral(bounds.Min)
+	if !ok {
+		return nil, fmt.Errorf("between operator expects a numeric min, got %v", bounds.Min)
+	}
+	maxText, ok := numberLiteral(bounds.Max)
+	if !ok {
+		return nil, fmt.Errorf("between operator expects a numeric max, got %v", bounds.Max)
+	}
+	minDec, _ := parseDecimal(minText)
+	maxDec, _ := parseDecimal(maxText)
+	if compareDecimal(minDec, maxDec) > 0 {
+		return nil, fmt.Errorf("between min %s exceeds max %s", minText, maxText)
+	}
+
+	open, closing := "[", "]"
+	if bounds.ExclusiveMin {
+		open = "("
+	}
+	if bounds.ExclusiveMax {
+		closing = ")"
+	}
+	return &BetweenOperator{
+		jsonPath:        jsonPath,
+		bounds:          bounds,
+		min:             minDec,
+		max:             maxDec,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		notNumberMsg:    "value at json path " + jsonPath + " is not a number",
+		mismatchMsg:     "value is not within " + open + minText + ", " + maxText + closing,
+	}, nil
+}
+
+// MustNewBetweenOperator panics when inputs are invalid.
+func MustNewBetweenOperator(jsonPath string, bounds BetweenBounds) *BetweenOperator {
+	op, err := NewBetweenOperator(jsonPath, bounds)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *BetweenOperator) Name() string {
+	return string(Between)
+}
+
+// Spec describes the operator.
+func (o *BetweenOperator) Spec() Spec {
+	cfg := map[string]interface{}{}
+	if o.bounds.Min != nil {
+		cfg["min"] = o.bounds.Min
+	}
+	if o.bounds.Max != nil {
+		cfg["max"] = o.bounds.Max
+	}
+	if o.bounds.ExclusiveMin {
+		cfg["exclusiveMin"] = true
+	}
+	if o.bounds.ExclusiveMax {
+		cfg["exclusiveMax"] = true
+	}
+	return Spec{Type: Between, Field: o.jsonPath, Value: cfg}
+}
+
+// Evaluate fetches the number at jsonPath and checks it against the interval.
+func (o *BetweenOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *BetweenOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *BetweenOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+	if actual.Type != gjson.Number {
+		return jsonfilter.ErrorResult(o.Name(), o.notNumberMsg)
+	}
+	value, ok := parseDecimal(actual.Raw)
+	if !ok {
+		return jsonfilter.ErrorResult(o.Name(), o.notNumberMsg)
+	}
+
+	lower := compareDecimal(value, o.min)
+	upper := compareDecimal(value, o.max)
+	if lower < 0 || upper > 0 || (o.bounds.ExclusiveMin && lower == 0) || (o.bounds.ExclusiveMax && upper == 0) {
+		return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+	}
+	return jsonfilter.ValidResult(o.Name())
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *BetweenOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if compareDecimal(o.min, o.max) > 0 {
+		return jsonfilter.ErrorValidationResult(o.Name(), "between min exceeds max")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// parseBetweenBounds reads an object with min, max, exclusiveMin and exclusiveMax keys.
+func parseBetweenBounds(value interface{}) (BetweenBounds, error) {
+	cfg, ok := toStringMap(value)
+	if !ok {
+		return BetweenBounds{}, fmt.Errorf("between operator expects an object with min and max, got %T", value)
+	}
+
+	var bounds BetweenBounds
+	for key, raw := range cfg {
+		switch key {
+		case "min":
+			bounds.Min = raw
+		case "max":
+			bounds.Max = raw
+		case "exclusiveMin", "exclusiveMax":
+			flag, ok := raw.(bool)
+			if !ok {
+				return BetweenBounds{}, fmt.Errorf("between option %s expects a boolean, got %T", key, raw)
+			}
+			if key == "exclusiveMin" {
+				bounds.ExclusiveMin = flag
+			} else {
+				bounds.ExclusiveMax = flag
+			}
+		default:
+			return BetweenBounds{}, fmt.Errorf("between operator does not support option %q", key)
+		}
+	}
+	if bounds.Min == nil || bounds.Max == nil {
+		return BetweenBounds{}, fmt.Errorf("between operator requires min and max")
+	}
+	return bounds, nil
+}
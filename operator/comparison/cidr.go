@@ -0,0 +1,241 @@
+package comparison
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// CIDROperator checks whether the IP address at a JSON path belongs to any of a set of CIDR prefixes.
+// Prefixes are compiled into sorted, merged address ranges per family, so a lookup is a binary search
+// regardless of the allowlist size. IPv4-mapped IPv6 addresses are matched as IPv4. For comma-separated
+// values such as X-Forwarded-For, the first (originating client) address is used.
+type CIDROperator struct {
+	jsonPath        string
+	prefixes        []netip.Prefix
+	v4              []ipRange
+	v6              []ipRange
+	pathNotFoundMsg string
+	invalidMsg      string
+	mismatchMsg     string
+}
+
+type ipRange struct {
+	from, to netip.Addr
+}
+
+// NewCIDROperator compiles the prefixes and constructs a CIDROperator. Bare addresses are treated as
+// single-host prefixes.
+func NewCIDROperator(jsonPath string, prefixes []string) (*CIDROperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if len(prefixes) == 0 {
+		return nil, fmt.Errorf("cidr operator requires at least one prefix")
+	}
+
+	op := &CIDROperator{
+		jsonPath:        jsonPath,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		invalidMsg:      "value at json path " + jsonPath + " is not an IP address",
+	}
+	for _, raw := range prefixes {
+		prefix, err := parsePrefix(raw)
+		if err != nil {
+			return nil, err
+		}
+		op.prefixes = append(op.prefixes, prefix)
+		r := ipRange{from: prefix.Addr(), to: lastAddr(prefix)}
+		if prefix.Addr().Is4() {
+			op.v4 = append(op.v4, r)
+		} else {
+			op.v6 = append(op.v6, r)
+		}
+	}
+	op.v4 = mergeRanges(op.v4)
+	op.v6 = mergeRanges(op.v6)
+	op.mismatchMsg = fmt.Sprintf("address is not within %s", strings.Join(prefixes, ", "))
+	return op, nil
+}
+
+// MustNewCIDROperator panics if construction fails.
+func MustNewCIDROperator(jsonPath string, prefixes []string) *CIDROperator {
+	op, err := NewCIDROperator(jsonPath, prefixes)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *CIDROperator) Name() string {
+	return string(CIDR)
+}
+
+// Spec describes the operator.
+func (o *CIDROperator) Spec() Spec {
+	prefixes := make([]interface{}, len(o.prefixes))
+	for i, prefix := range o.prefixes {
+		prefixes[i] = prefix.String()
+	}
+	return Spec{Type: CIDR, Field: o.jsonPath, Value: prefixes}
+}
+
+// Evaluate parses the address at jsonPath and looks it up in the compiled ranges.
+func (o *CIDROperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *CIDROperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *CIDROperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	value := actual.Str
+	if i := strings.IndexByte(value, ','); i >= 0 {
+		value = value[:i]
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(value))
+	if err != nil {
+		return jsonfilter.ErrorResult(o.Name(), o.invalidMsg)
+	}
+	addr = addr.Unmap().WithZone("")
+
+	ranges := o.v6
+	if addr.Is4() {
+		ranges = o.v4
+	}
+	if containsAddr(ranges, addr) {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate re-validates invariant fields.
+func (o *CIDROperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if len(o.v4) == 0 && len(o.v6) == 0 {
+		return jsonfilter.ErrorValidationResult(o.Name(), "cidr operator requires at least one prefix")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// parsePrefix parses a CIDR prefix or bare address, masks host bits and folds IPv4-mapped IPv6
+// prefixes (::ffff:0:0/96 and longer) into their IPv4 form.
+func parsePrefix(raw string) (netip.Prefix, error) {
+	raw = strings.TrimSpace(raw)
+	var prefix netip.Prefix
+	if strings.Contains(raw, "/") {
+		p, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid cidr prefix %q: %w", raw, err)
+		}
+		prefix = p
+	} else {
+		addr, err := netip.ParseAddr(raw)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid ip address %q: %w", raw, err)
+		}
+		prefix = netip.PrefixFrom(addr.WithZone(""), addr.BitLen())
+	}
+
+	addr := prefix.Addr()
+	if addr.Is4In6() {
+		if prefix.Bits() < 96 {
+			return netip.Prefix{}, fmt.Errorf("ipv4-mapped prefix %q must be at least /96", raw)
+		}
+		prefix = netip.PrefixFrom(addr.Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked(), nil
+}
+
+// lastAddr returns the highest address covered by a masked prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr()
+	if addr.Is4() {
+		b := addr.As4()
+		setHostBits(b[:], prefix.Bits())
+		return netip.AddrFrom4(b)
+	}
+	b := addr.As16()
+	setHostBits(b[:], prefix.Bits())
+	return netip.AddrFrom16(b)
+}
+
+func setHostBits(b []byte, bits int) {
+	for i := range b {
+		switch {
+		case bits >= 8:
+			bits -= 8
+		case bits > 0:
+			b[i] |= 0xff >> bits
+			bits = 0
+		default:
+			b[i] = 0xff
+		}
+	}
+}
+
+// mergeRanges sorts ranges and coalesces overlapping or adjacent entries.
+func mergeRanges(ranges []ipRange) []ipRange {
+	if len(ranges) < 2 {
+		return ranges
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].from.Less(ranges[j].from)
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		next := last.to.Next()
+		if r.from.Compare(last.to) <= 0 || (next.IsValid() && r.from == next) {
+			if last.to.Less(r.to) {
+				last.to = r.to
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func containsAddr(ranges []ipRange, addr netip.Addr) bool {
+	i := sort.Search(len(ranges), func(i int) bool {
+		return !ranges[i].to.Less(addr)
+	})
+	return i < len(ranges) && !addr.Less(ranges[i].from)
+}
+
+// parsePrefixList accepts a single prefix string or a list of prefix strings.
+func parsePrefixList(value interface{}) ([]string, error) {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}, nil
+	case []interface{}:
+		prefixes := make([]string, 0, len(typed))
+		for _, item := range typed {
+			prefix, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("cidr operator expects string prefixes, got %T", item)
+			}
+			prefixes = append(prefixes, prefix)
+		}
+		return prefixes, nil
+	case []string:
+		return typed, nil
+	default:
+		return nil, fmt.Errorf("cidr operator expects a prefix or list of prefixes, got %T", value)
+	}
+}
@@ -0,0 +1,51 @@
+package comparison
+
+import "testing"
+
+func TestCIDROperator(t *testing.T) {
+	op := MustNewCIDROperator("ip", []string{"10.0.0.0/8", "192.168.1.0/24", "192.168.2.0/24", "2001:db8::/32", "::ffff:172.16.0.0/108", "203.0.113.7"})
+	cases := []struct {
+		ip    string
+		match bool
+	}{
+		{"10.1.2.3", true},
+		{"11.0.0.0", false},
+		{"192.168.1.255", true},
+		{"192.168.2.0", true},
+		{"192.168.3.0", false},
+		{"2001:db8::1", true},
+		{"2001:db9::1", false},
+		{"::ffff:10.0.0.1", true},
+		{"172.16.5.5", true},
+		{"172.32.0.1", false},
+		{"203.0.113.7", true},
+		{"203.0.113.8", false},
+		{"fe80::1%eth0", false},
+		{"10.9.8.7, 8.8.8.8", true},
+		{"8.8.8.8, 10.9.8.7", false},
+	}
+	for _, tc := range cases {
+		res := op.Evaluate([]byte(`{"ip":"` + tc.ip + `"}`))
+		if res.Match != tc.match {
+			t.Fatalf("%s: unexpected result %#v", tc.ip, res)
+		}
+	}
+
+	res := op.Evaluate([]byte(`{"ip":"not-an-ip"}`))
+	if res.Match || res.CauseDescription != "value at json path ip is not an IP address" {
+		t.Fatalf("expected invalid address to fail: %#v", res)
+	}
+}
+
+func TestCIDROperatorMergesRanges(t *testing.T) {
+	op := MustNewCIDROperator("ip", []string{"10.0.1.0/24", "10.0.0.0/24", "10.0.0.128/25", "10.0.2.0/23", "0.0.0.0/0"})
+	if len(op.v4) != 1 || op.v4[0].from.String() != "0.0.0.0" || op.v4[0].to.String() != "255.255.255.255" {
+		t.Fatalf("expected ranges to merge into one: %v", op.v4)
+	}
+
+	for _, prefixes := range [][]string{nil, {"10.0.0.0/33"}, {"::ffff:10.0.0.0/64"}, {"nope"}} {
+		if _, err := NewCIDROperator("ip", prefixes); err == nil {
+			t.Fatalf("expected %v to be rejected", prefixes)
+		}
+	}
+}
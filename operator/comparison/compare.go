@@ -0,0 +1,127 @@
+package comparison
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/tidwall/gjson"
+)
+
+// literalResult converts a scalar literal from a filter definition into a gjson.Result so that
+// literals and values resolved from the payload can share the same comparison routines.
+func literalResult(value interface{}) (gjson.Result, bool) {
+	switch typed := value.(type) {
+	case string:
+		return gjson.Result{Type: gjson.String, Str: typed}, true
+	case bool:
+		if typed {
+			return gjson.Result{Type: gjson.True, Raw: "true"}, true
+		}
+		return gjson.Result{Type: gjson.False, Raw: "false"}, true
+	case nil:
+		return gjson.Result{Type: gjson.Null, Raw: "null"}, true
+	default:
+		raw, ok := numberLiteral(value)
+		if !ok {
+			return gjson.Result{}, false
+		}
+		num, _ := strconv.ParseFloat(raw, 64)
+		return gjson.Result{Type: gjson.Number, Num: num, Raw: raw}, true
+	}
+}
+
+// compareResults orders two values of the same kind. Numbers are compared as exact decimals and strings
+// lexicographically; any other combination is reported as not comparable.
+func compareResults(a, b gjson.Result) (int, bool) {
+	switch {
+	case a.Type == gjson.Number && b.Type == gjson.Number:
+		if cmp, ok := compareNumberLiterals(a.Raw, b.Raw); ok {
+			return cmp, true
+		}
+		switch {
+		case a.Num < b.Num:
+			return -1, true
+		case a.Num > b.Num:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case a.Type == gjson.String && b.Type == gjson.String:
+		switch {
+		case a.Str < b.Str:
+			return -1, true
+		case a.Str > b.Str:
+			return 1, true
+		default:
+			return 0, true
+		}
+	default:
+		return 0, false
+	}
+}
+
+// equalResults reports whether two resolved values are equal. Scalars are compared by type and
+// value; objects and arrays are compared structurally.
+func equalResults(a, b gjson.Result) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	switch a.Type {
+	case gjson.String:
+		return a.Str == b.Str
+	case gjson.Number:
+		if cmp, ok := compareNumberLiterals(a.Raw, b.Raw); ok {
+			return cmp == 0
+		}
+		return a.Num == b.Num
+	case gjson.JSON:
+		if a.Raw == b.Raw {
+			return true
+		}
+		return reflect.DeepEqual(a.Value(), b.Value())
+	default:
+		return true
+	}
+}
+
+// satisfiesOrdering reports whether the comparison outcome cmp fulfils the ordering type.
+func satisfiesOrdering(t Type, cmp int) bool {
+	switch t {
+	case LessThan:
+		return cmp < 0
+	case LessEqual:
+		return cmp <= 0
+	case GreaterThan:
+		return cmp > 0
+	case GreaterEqual:
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+// isOrdering reports whether t is one of lt/le/gt/ge.
+func isOrdering(t Type) bool {
+	switch t {
+	case LessThan, LessEqual, GreaterThan, GreaterEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+// orderingPhrase renders the ordering type for failure descriptions.
+func orderingPhrase(t Type) string {
+	switch t {
+	case LessThan:
+		return "less than"
+	case LessEqual:
+		return "less than or equal to"
+	case GreaterThan:
+		return "greater than"
+	case GreaterEqual:
+		return "greater than or equal to"
+	default:
+		return string(t)
+	}
+}
@@ -0,0 +1,211 @@
+package comparison
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// decimal is an exact, allocation-free view over a JSON number literal such as -12.50e3. The value is
+// 0.d1d2d3... × 10^point, where the significant digits are read from the integer and fraction parts
+// without leading or trailing zeros.
+type decimal struct {
+	neg        bool
+	int, frac  string
+	start, end int // significant digit window over int+frac
+	point      int
+}
+
+// maxDecimalExponent bounds parsed exponents so that pathological literals cannot overflow.
+const maxDecimalExponent = 1 << 30
+
+// parseDecimal parses a JSON number literal. Leading '+' signs and missing integer digits are
+// rejected, matching the JSON grammar.
+func parseDecimal(s string) (decimal, bool) {
+	var d decimal
+	if s == "" {
+		return d, false
+	}
+	i := 0
+	if s[0] == '-' {
+		d.neg = true
+		i++
+	}
+	intStart := i
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	if i == intStart {
+		return d, false
+	}
+	d.int = s[intStart:i]
+	if i < len(s) && s[i] == '.' {
+		i++
+		fracStart := i
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		if i == fracStart {
+			return d, false
+		}
+		d.frac = s[fracStart:i]
+	}
+	exp := 0
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		expNeg := false
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			expNeg = s[i] == '-'
+			i++
+		}
+		expStart := i
+		for i < len(s) && isDigit(s[i]) {
+			if exp < maxDecimalExponent {
+				exp = exp*10 + int(s[i]-'0')
+			}
+			i++
+		}
+		if i == expStart {
+			return d, false
+		}
+		if expNeg {
+			exp = -exp
+		}
+	}
+	if i != len(s) {
+		return d, false
+	}
+
+	total := len(d.int) + len(d.frac)
+	d.start, d.end = 0, total
+	for d.start < total && d.digit(d.start) == '0' {
+		d.start++
+	}
+	for d.end > d.start && d.digit(d.end-1) == '0' {
+		d.end--
+	}
+	d.point = len(d.int) - d.start + exp
+	return d, true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (d decimal) digit(i int) byte {
+	if i < len(d.int) {
+		return d.int[i]
+	}
+	return d.frac[i-len(d.int)]
+}
+
+func (d decimal) isZero() bool {
+	return d.start == d.end
+}
+
+// compareDecimal orders two decimals exactly.
+func compareDecimal(a, b decimal) int {
+	switch {
+	case a.isZero() && b.isZero():
+		return 0
+	case a.isZero():
+		if b.neg {
+			return 1
+		}
+		return -1
+	case b.isZero():
+		if a.neg {
+			return -1
+		}
+		return 1
+	case a.neg != b.neg:
+		if a.neg {
+			return -1
+		}
+		return 1
+	}
+
+	sign := 1
+	if a.neg {
+		sign = -1
+	}
+	if a.point != b.point {
+		if a.point < b.point {
+			return -sign
+		}
+		return sign
+	}
+	i, j := a.start, b.start
+	for ; i < a.end && j < b.end; i, j = i+1, j+1 {
+		if ca, cb := a.digit(i), b.digit(j); ca != cb {
+			if ca < cb {
+				return -sign
+			}
+			return sign
+		}
+	}
+	switch {
+	case i < a.end:
+		return sign
+	case j < b.end:
+		return -sign
+	default:
+		return 0
+	}
+}
+
+// compareNumberLiterals exactly orders two JSON number literals, reporting false if either is malformed.
+func compareNumberLiterals(a, b string) (int, bool) {
+	da, ok := parseDecimal(a)
+	if !ok {
+		return 0, false
+	}
+	db, ok := parseDecimal(b)
+	if !ok {
+		return 0, false
+	}
+	return compareDecimal(da, db), true
+}
+
+// numberLiteral renders a numeric filter literal as exact decimal text. Floats use the shortest
+// representation that round-trips, so 0.1 stays "0.1"; json.Number and numeric strings keep their
+// original digits, which preserves integers beyond int64 and exact decimal amounts.
+func numberLiteral(value interface{}) (string, bool) {
+	switch typed := value.(type) {
+	case json.Number:
+		if _, ok := parseDecimal(string(typed)); ok {
+			return string(typed), true
+		}
+		return "", false
+	case string:
+		if _, ok := parseDecimal(typed); ok {
+			return typed, true
+		}
+		return "", false
+	case float64:
+		return strconv.FormatFloat(typed, 'g', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(typed), 'g', -1, 32), true
+	case int:
+		return strconv.Itoa(typed), true
+	case int8:
+		return strconv.FormatInt(int64(typed), 10), true
+	case int16:
+		return strconv.FormatInt(int64(typed), 10), true
+	case int32:
+		return strconv.FormatInt(int64(typed), 10), true
+	case int64:
+		return strconv.FormatInt(typed, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(typed), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(typed), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(typed), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(typed), 10), true
+	case uint64:
+		return strconv.FormatUint(typed, 10), true
+	default:
+		return "", false
+	}
+}
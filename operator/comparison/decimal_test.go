@@ -0,0 +1,35 @@
+package comparison
+
+import "testing"
+
+func TestCompareNumberLiterals(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1", "1.0", 0},
+		{"0.1", "1e-1", 0},
+		{"100", "1E2", 0},
+		{"-0", "0.000", 0},
+		{"0.30000000000000004", "0.3", 1},
+		{"9223372036854775808", "9223372036854775807", 1},
+		{"123456789012345678901234567890", "123456789012345678901234567891", -1},
+		{"-5", "3", -1},
+		{"-5.5", "-5.25", -1},
+		{"12.50", "12.5", 0},
+		{"0.001", "0.01", -1},
+		{"1e400", "1e399", 1},
+	}
+	for _, tc := range cases {
+		got, ok := compareNumberLiterals(tc.a, tc.b)
+		if !ok || got != tc.want {
+			t.Fatalf("compare(%s, %s) = %d, %v; want %d", tc.a, tc.b, got, ok, tc.want)
+		}
+	}
+
+	for _, invalid := range []string{"", "-", "1.", ".5", "+1", "1e", "0x10", "NaN"} {
+		if _, ok := parseDecimal(invalid); ok {
+			t.Fatalf("expected %q to be rejected", invalid)
+		}
+	}
+}
@@ -12,20 +12,28 @@ import (
 type EqualOperator struct {
 	jsonPath        string
 	expected        interface{}
+	expectedNumber  string
+	numeric         bool
 	pathNotFoundMsg string
 	mismatchMsg     string
 }
 
 // NewEqualOperator constructs an EqualOperator instance.
 func NewEqualOperator(jsonPath string, expected interface{}) (*EqualOperator, error) {
-	if jsonPath == "" {
-		return nil, fmt.Errorf("json path must not be empty")
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
 	}
 	op := &EqualOperator{
 		jsonPath:        jsonPath,
 		expected:        expected,
 		pathNotFoundMsg: "json path " + jsonPath + " not found",
 	}
+	if _, isString := expected.(string); !isString {
+		op.expectedNumber, op.numeric = numberLiteral(expected)
+	}
+	if !op.numeric {
+		op.expected = normalizeLiteral(expected)
+	}
 	op.mismatchMsg = fmt.Sprintf("value did not equal expected %v", expected)
 	return op, nil
 }
@@ -44,9 +52,23 @@ func (o *EqualOperator) Name() string {
 	return string(Equal)
 }
 
+// Spec describes the operator.
+func (o *EqualOperator) Spec() Spec {
+	return Spec{Type: Equal, Field: o.jsonPath, Value: o.expected}
+}
+
 // Evaluate fetches the JSON value and compares it to the expected value.
 func (o *EqualOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
-	actual := getJSONResult(json, o.jsonPath)
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *EqualOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *EqualOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
 	if !actual.Exists() {
 		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
 	}
@@ -67,6 +89,9 @@ func (o *EqualOperator) Validate() jsonfilter.ValidationResult {
 }
 
 func (o *EqualOperator) matches(actual gjson.Result) bool {
+	if o.numeric {
+		return matchesNumber(actual, o.expectedNumber)
+	}
 	switch expected := o.expected.(type) {
 	case string:
 		return actual.Str == expected
@@ -74,33 +99,93 @@ func (o *EqualOperator) matches(actual gjson.Result) bool {
 		return actual.Str == expected.String()
 	case bool:
 		return actual.Bool() == expected
-	case int:
-		return actual.Int() == int64(expected)
-	case int8:
-		return actual.Int() == int64(expected)
-	case int16:
-		return actual.Int() == int64(expected)
-	case int32:
-		return actual.Int() == int64(expected)
-	case int64:
-		return actual.Int() == expected
-	case uint:
-		return actual.Uint() == uint64(expected)
-	case uint8:
-		return actual.Uint() == uint64(expected)
-	case uint16:
-		return actual.Uint() == uint64(expected)
-	case uint32:
-		return actual.Uint() == uint64(expected)
-	case uint64:
-		return actual.Uint() == expected
-	case float32:
-		return actual.Float() == float64(expected)
-	case float64:
-		return actual.Float() == expected
 	case nil:
 		return !actual.Exists() || actual.Type == gjson.Null
 	default:
 		return reflect.DeepEqual(actual.Value(), expected)
 	}
 }
+
+// matchesNumber compares a payload number, or a numeric string, with the expected literal as exact
+// decimals, so that integers beyond int64 and values such as 0.1 are not subject to float rounding.
+func matchesNumber(actual gjson.Result, expected string) bool {
+	var raw string
+	switch actual.Type {
+	case gjson.Number:
+		raw = actual.Raw
+	case gjson.String:
+		raw = actual.Str
+	default:
+		return false
+	}
+	cmp, ok := compareNumberLiterals(raw, expected)
+	return ok && cmp == 0
+}
+
+// NotEqualOperator matches when a JSON path value differs from an expected literal.
+type NotEqualOperator struct {
+	equal      *EqualOperator
+	matchedMsg string
+}
+
+// NewNotEqualOperator constructs a NotEqualOperator instance.
+func NewNotEqualOperator(jsonPath string, expected interface{}) (*NotEqualOperator, error) {
+	equal, err := NewEqualOperator(jsonPath, expected)
+	if err != nil {
+		return nil, err
+	}
+	return &NotEqualOperator{
+		equal:      equal,
+		matchedMsg: fmt.Sprintf("value equals %v", expected),
+	}, nil
+}
+
+// MustNewNotEqualOperator panics when inputs are invalid.
+func MustNewNotEqualOperator(jsonPath string, expected interface{}) *NotEqualOperator {
+	op, err := NewNotEqualOperator(jsonPath, expected)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *NotEqualOperator) Name() string {
+	return string(NotEqual)
+}
+
+// Spec describes the operator.
+func (o *NotEqualOperator) Spec() Spec {
+	return Spec{Type: NotEqual, Field: o.equal.jsonPath, Value: o.equal.expected}
+}
+
+// Evaluate fetches the JSON value and succeeds when it differs from the expected value.
+func (o *NotEqualOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.equal.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *NotEqualOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.equal.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *NotEqualOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.equal.pathNotFoundMsg)
+	}
+
+	if o.equal.matches(actual) {
+		return jsonfilter.ErrorResult(o.Name(), o.matchedMsg)
+	}
+
+	return jsonfilter.ValidResult(o.Name())
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *NotEqualOperator) Validate() jsonfilter.ValidationResult {
+	if o.equal == nil || o.equal.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
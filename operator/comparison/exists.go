@@ -0,0 +1,233 @@
+package comparison
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// ExistsOperator checks whether a JSON path is present (value true) or absent (value false).
+type ExistsOperator struct {
+	jsonPath    string
+	want        bool
+	mismatchMsg string
+}
+
+// NewExistsOperator constructs an ExistsOperator.
+func NewExistsOperator(jsonPath string, want bool) (*ExistsOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	msg := "json path " + jsonPath + " not found"
+	if !want {
+		msg = "json path " + jsonPath + " exists"
+	}
+	return &ExistsOperator{jsonPath: jsonPath, want: want, mismatchMsg: msg}, nil
+}
+
+// MustNewExistsOperator panics when inputs are invalid.
+func MustNewExistsOperator(jsonPath string, want bool) *ExistsOperator {
+	op, err := NewExistsOperator(jsonPath, want)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *ExistsOperator) Name() string {
+	return string(Exists)
+}
+
+// Spec describes the operator.
+func (o *ExistsOperator) Spec() Spec {
+	return Spec{Type: Exists, Field: o.jsonPath, Value: o.want}
+}
+
+// Evaluate checks the presence of jsonPath.
+func (o *ExistsOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *ExistsOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *ExistsOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if actual.Exists() == o.want {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *ExistsOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// JSON type names accepted by TypeOperator. Integer matches numbers without a fractional part.
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeNull    = "null"
+)
+
+var jsonTypeNames = map[string]struct{}{
+	TypeString:  {},
+	TypeNumber:  {},
+	TypeInteger: {},
+	TypeBoolean: {},
+	TypeObject:  {},
+	TypeArray:   {},
+	TypeNull:    {},
+}
+
+// TypeOperator checks that the value at a JSON path has one of the listed JSON types.
+type TypeOperator struct {
+	jsonPath        string
+	types           []string
+	pathNotFoundMsg string
+	mismatchMsg     string
+}
+
+// NewTypeOperator constructs a TypeOperator for one or more JSON type names.
+func NewTypeOperator(jsonPath string, types ...string) (*TypeOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("type operator requires at least one type")
+	}
+	for _, name := range types {
+		if _, ok := jsonTypeNames[name]; !ok {
+			known := make([]string, 0, len(jsonTypeNames))
+			for typeName := range jsonTypeNames {
+				known = append(known, typeName)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("type %q is not one of %s", name, strings.Join(known, ", "))
+		}
+	}
+	return &TypeOperator{
+		jsonPath:        jsonPath,
+		types:           types,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		mismatchMsg:     "value is not of type " + strings.Join(types, " or "),
+	}, nil
+}
+
+// MustNewTypeOperator panics when inputs are invalid.
+func MustNewTypeOperator(jsonPath string, types ...string) *TypeOperator {
+	op, err := NewTypeOperator(jsonPath, types...)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *TypeOperator) Name() string {
+	return string(JSONType)
+}
+
+// Spec describes the operator.
+func (o *TypeOperator) Spec() Spec {
+	if len(o.types) == 1 {
+		return Spec{Type: JSONType, Field: o.jsonPath, Value: o.types[0]}
+	}
+	types := make([]interface{}, len(o.types))
+	for i, name := range o.types {
+		types[i] = name
+	}
+	return Spec{Type: JSONType, Field: o.jsonPath, Value: types}
+}
+
+// Evaluate checks the JSON type of the value at jsonPath.
+func (o *TypeOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *TypeOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *TypeOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+	for _, name := range o.types {
+		if hasJSONType(actual, name) {
+			return jsonfilter.ValidResult(o.Name())
+		}
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+func hasJSONType(actual gjson.Result, name string) bool {
+	switch name {
+	case TypeString:
+		return actual.Type == gjson.String
+	case TypeNumber:
+		return actual.Type == gjson.Number
+	case TypeInteger:
+		return actual.Type == gjson.Number && actual.Num == math.Trunc(actual.Num)
+	case TypeBoolean:
+		return actual.Type == gjson.True || actual.Type == gjson.False
+	case TypeObject:
+		return actual.IsObject()
+	case TypeArray:
+		return actual.IsArray()
+	case TypeNull:
+		return actual.Type == gjson.Null
+	default:
+		return false
+	}
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *TypeOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if len(o.types) == 0 {
+		return jsonfilter.ErrorValidationResult(o.Name(), "type operator requires at least one type")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// parseTypeNames accepts a type name or a list of type names.
+func parseTypeNames(value interface{}) ([]string, error) {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}, nil
+	case []string:
+		return typed, nil
+	case []interface{}:
+		names := make([]string, 0, len(typed))
+		for _, item := range typed {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("type operator expects type names, got %T", item)
+			}
+			names = append(names, name)
+		}
+		return names, nil
+	default:
+		return nil, fmt.Errorf("type operator expects a type name or list of type names, got %T", value)
+	}
+}
@@ -0,0 +1,52 @@
+package comparison
+
+import (
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// explain attaches a Cause to a result that did not match when ctx explains its evaluations. The
+// code is derived from the operator type and the value found, so operators keep their precomputed
+// messages and ordinary evaluation is unaffected.
+func explain(ctx *jsonfilter.Context, op Describer, actual gjson.Result, res jsonfilter.EvaluationResult) jsonfilter.EvaluationResult {
+	if res.Match || !ctx.Explaining() {
+		return res
+	}
+	spec := op.Spec()
+	res.Cause = jsonfilter.NewCause(causeCode(op, spec, actual, res), spec.Field, spec.Value, actual.Raw)
+	return res
+}
+
+func causeCode(op Describer, spec Spec, actual gjson.Result, res jsonfilter.EvaluationResult) jsonfilter.CauseCode {
+	if res.Error {
+		return jsonfilter.CauseEvaluationError
+	}
+	if !actual.Exists() {
+		return jsonfilter.CausePathNotFound
+	}
+	if _, textual := op.(*TextOperator); textual || acceptsType(spec, actual) {
+		return jsonfilter.CauseMismatch
+	}
+	return jsonfilter.CauseTypeMismatch
+}
+
+// acceptsType reports whether an operator of spec's type can compare a value of actual's JSON type.
+func acceptsType(spec Spec, actual gjson.Result) bool {
+	switch spec.Type {
+	case Regex, StartsWith, EndsWith, EqualFold, Like, Semver, CIDR, Format:
+		return actual.Type == gjson.String
+	case Before, After, Within:
+		return actual.Type == gjson.String || actual.Type == gjson.Number
+	case Between, Approx:
+		return actual.Type == gjson.Number
+	case LessThan, LessEqual, GreaterThan, GreaterEqual:
+		literal, _ := literalResult(spec.Value)
+		return actual.Type == literal.Type
+	case Contains, NotContains:
+		return actual.Type == gjson.String || actual.IsArray()
+	case Size:
+		return actual.Type == gjson.String || actual.IsArray() || actual.IsObject()
+	default:
+		return true
+	}
+}
@@ -2,12 +2,49 @@ package comparison
 
 import (
 	"fmt"
+	"time"
 
 	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
 )
 
+// Option customises operators created by Instantiate.
+type Option func(*instantiateConfig)
+
+type instantiateConfig struct {
+	clock   func() time.Time
+	textual bool
+}
+
+// WithClock sets the clock used by time operators to resolve relative bounds such as "now-15m".
+func WithClock(clock func() time.Time) Option {
+	return func(cfg *instantiateConfig) {
+		cfg.clock = clock
+	}
+}
+
+// WithTextualValues makes eq, ne and rx compare the textual form of payload values, as
+// telekom/JSON-Filter does; see TextOperator.
+func WithTextualValues() Option {
+	return func(cfg *instantiateConfig) {
+		cfg.textual = true
+	}
+}
+
 // Instantiate creates a comparison operator implementation for the provided type.
-func Instantiate(t Type, field string, value interface{}) (jsonfilter.Operator, error) {
+func Instantiate(t Type, field string, value interface{}, opts ...Option) (jsonfilter.Operator, error) {
+	var cfg instantiateConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.textual && (t == Equal || t == NotEqual || t == Regex) {
+		op, err := NewTextOperator(t, field, value)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	}
+
 	switch t {
 	case Equal:
 		op, err := NewEqualOperator(field, value)
@@ -15,6 +52,18 @@ func Instantiate(t Type, field string, value interface{}) (jsonfilter.Operator,
 			return nil, err
 		}
 		return op, nil
+	case NotEqual:
+		op, err := NewNotEqualOperator(field, value)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case LessThan, LessEqual, GreaterThan, GreaterEqual:
+		op, err := NewOrderingOperator(t, field, value)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
 	case Regex:
 		pattern, ok := value.(string)
 		if !ok {
@@ -25,7 +74,158 @@ func Instantiate(t Type, field string, value interface{}) (jsonfilter.Operator,
 			return nil, err
 		}
 		return op, nil
+	case StartsWith, EndsWith, EqualFold:
+		expected, opts, err := parseStringPredicate(t, value)
+		if err != nil {
+			return nil, err
+		}
+		op, err := NewStringOperator(t, field, expected, opts)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Like:
+		pattern, opts, err := parseGlob(value)
+		if err != nil {
+			return nil, err
+		}
+		op, err := NewGlobOperator(field, pattern, opts)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Semver:
+		expression, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("semver operator expects string range, got %T", value)
+		}
+		op, err := NewSemverOperator(field, expression)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Before, After, Within:
+		from, to, timeOpts, err := parseTimeConfig(t, value)
+		if err != nil {
+			return nil, err
+		}
+		timeOpts.Clock = cfg.clock
+		var op *TimeOperator
+		switch t {
+		case Before:
+			op, err = NewTimeOperator(t, field, *to, timeOpts)
+		case After:
+			op, err = NewTimeOperator(t, field, *from, timeOpts)
+		default:
+			op, err = NewWithinOperator(field, from, to, timeOpts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case CIDR:
+		prefixes, err := parsePrefixList(value)
+		if err != nil {
+			return nil, err
+		}
+		op, err := NewCIDROperator(field, prefixes)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Between:
+		bounds, err := parseBetweenBounds(value)
+		if err != nil {
+			return nil, err
+		}
+		op, err := NewBetweenOperator(field, bounds)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Approx:
+		expected, tolerance, err := parseApprox(value)
+		if err != nil {
+			return nil, err
+		}
+		op, err := NewApproxOperator(field, expected, tolerance)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Format:
+		name, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("format operator expects string format name, got %T", value)
+		}
+		op, err := NewFormatOperator(field, name)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Schema:
+		op, err := NewSchemaOperator(field, value)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case In, NotIn:
+		values, err := parseValueList(t, value)
+		if err != nil {
+			return nil, err
+		}
+		op, err := NewMembershipOperator(t, field, values)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Contains, NotContains:
+		op, err := NewContainsOperator(t, field, value)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Exists:
+		want, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("exists operator expects a boolean value, got %T", value)
+		}
+		op, err := NewExistsOperator(field, want)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case JSONType:
+		names, err := parseTypeNames(value)
+		if err != nil {
+			return nil, err
+		}
+		op, err := NewTypeOperator(field, names...)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
+	case Size:
+		bounds, err := parseSizeBounds(value)
+		if err != nil {
+			return nil, err
+		}
+		op, err := NewSizeOperator(field, bounds)
+		if err != nil {
+			return nil, err
+		}
+		return op, nil
 	default:
 		return nil, fmt.Errorf("comparison operator %s is not implemented", t)
 	}
 }
+
+// InstantiateReference creates a comparison operator whose right-hand side is resolved from refField
+// in the same payload instead of a literal value.
+func InstantiateReference(t Type, field, refField string) (jsonfilter.Operator, error) {
+	op, err := NewReferenceOperator(t, field, refField)
+	if err != nil {
+		return nil, err
+	}
+	return op, nil
+}
@@ -0,0 +1,147 @@
+package comparison
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// FormatChecker reports whether a string conforms to a named format.
+type FormatChecker func(value string) bool
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]FormatChecker{
+		"uuid":      isUUID,
+		"email":     isEmail,
+		"uri":       isURI,
+		"ipv4":      isIPv4,
+		"ipv6":      isIPv6,
+		"date":      isDate,
+		"date-time": isDateTime,
+		"hostname":  isHostname,
+		"base64":    isBase64,
+	}
+)
+
+// RegisterFormat adds or replaces a named format in the catalog used by FormatOperator. Operators
+// resolve their checker at construction, so registration should happen during program start-up.
+func RegisterFormat(name string, checker FormatChecker) error {
+	if name == "" {
+		return fmt.Errorf("format name must not be empty")
+	}
+	if checker == nil {
+		return fmt.Errorf("format %s requires a checker", name)
+	}
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[name] = checker
+	return nil
+}
+
+// Formats lists the registered format names in sorted order.
+func Formats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupFormat(name string) (FormatChecker, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	checker, ok := formats[name]
+	return checker, ok
+}
+
+// FormatOperator checks that the string at a JSON path conforms to a named format such as uuid,
+// email or date-time. The built-in catalog follows the JSON Schema format keywords.
+type FormatOperator struct {
+	jsonPath        string
+	format          string
+	check           FormatChecker
+	pathNotFoundMsg string
+	notStringMsg    string
+	mismatchMsg     string
+}
+
+// NewFormatOperator constructs a FormatOperator for a registered format.
+func NewFormatOperator(jsonPath, format string) (*FormatOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	check, ok := lookupFormat(format)
+	if !ok {
+		return nil, fmt.Errorf("format %q is not registered", format)
+	}
+	return &FormatOperator{
+		jsonPath:        jsonPath,
+		format:          format,
+		check:           check,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		notStringMsg:    "value at json path " + jsonPath + " is not a string",
+		mismatchMsg:     "value is not a valid " + format,
+	}, nil
+}
+
+// MustNewFormatOperator panics if construction fails.
+func MustNewFormatOperator(jsonPath, format string) *FormatOperator {
+	op, err := NewFormatOperator(jsonPath, format)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *FormatOperator) Name() string {
+	return string(Format)
+}
+
+// Spec describes the operator.
+func (o *FormatOperator) Spec() Spec {
+	return Spec{Type: Format, Field: o.jsonPath, Value: o.format}
+}
+
+// Evaluate checks the string at jsonPath against the format.
+func (o *FormatOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *FormatOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *FormatOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+	if actual.Type != gjson.String {
+		return jsonfilter.ErrorResult(o.Name(), o.notStringMsg)
+	}
+
+	if o.check(actual.Str) {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate re-validates invariant fields.
+func (o *FormatOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if o.check == nil {
+		return jsonfilter.ErrorValidationResult(o.Name(), "format operator must have a checker")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
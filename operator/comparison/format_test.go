@@ -0,0 +1,101 @@
+package comparison
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinFormats(t *testing.T) {
+	cases := map[string]struct {
+		valid, invalid []string
+	}{
+		"uuid": {
+			valid:   []string{"123e4567-e89b-12d3-a456-426614174000", "123E4567-E89B-12D3-A456-426614174000"},
+			invalid: []string{"123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g", ""},
+		},
+		"email": {
+			valid:   []string{"joe.bloggs@example.com", "o'hara+tag@sub.example.co.uk"},
+			invalid: []string{"@example.com", "joe@", ".joe@example.com", "jo..e@example.com", "joe@-example.com", "joe bloggs@example.com"},
+		},
+		"uri": {
+			valid:   []string{"https://example.com/a?b=c#d", "urn:isbn:0451450523", "mailto:joe@example.com", "http://[::1]:80/%20"},
+			invalid: []string{"//example.com", "example.com", "1http://x", "http://exa mple.com", "http://x/%zz"},
+		},
+		"ipv4": {
+			valid:   []string{"192.168.0.1", "0.0.0.0"},
+			invalid: []string{"256.0.0.1", "01.2.3.4", "1.2.3", "::1"},
+		},
+		"ipv6": {
+			valid:   []string{"::1", "2001:db8::8a2e:370:7334", "::ffff:192.0.2.1"},
+			invalid: []string{"1.2.3.4", "fe80::1%eth0", "2001:db8:::1"},
+		},
+		"date": {
+			valid:   []string{"2024-02-29", "2000-02-29", "1999-12-31"},
+			invalid: []string{"2023-02-29", "1900-02-29", "2024-13-01", "2024-04-31", "2024-1-01"},
+		},
+		"date-time": {
+			valid:   []string{"2024-05-01T12:00:00Z", "2024-05-01t12:00:00.123456z", "2016-12-31T23:59:60+01:00"},
+			invalid: []string{"2024-05-01 12:00:00Z", "2024-05-01T24:00:00Z", "2024-05-01T12:00:00", "2024-05-01T12:00:00.Z", "2024-05-01T12:00:00+0100"},
+		},
+		"hostname": {
+			valid:   []string{"example.com", "a-b.c1", "localhost"},
+			invalid: []string{"-example.com", "example-.com", "exa_mple.com", "a..b", strings.Repeat("a", 64) + ".com"},
+		},
+		"base64": {
+			valid:   []string{"", "Zm9v", "Zm9vYg==", "Zm9vYmE=", "+/+/"},
+			invalid: []string{"Zm9", "Zm9v=", "Zm=v", "Zm9-", "Z==="},
+		},
+	}
+	for format, tc := range cases {
+		op := MustNewFormatOperator("v", format)
+		for _, value := range tc.valid {
+			if res := op.Evaluate([]byte(`{"v":"` + value + `"}`)); !res.Match {
+				t.Fatalf("%s: expected %q to be valid: %#v", format, value, res)
+			}
+		}
+		for _, value := range tc.invalid {
+			if res := op.Evaluate([]byte(`{"v":"` + value + `"}`)); res.Match {
+				t.Fatalf("%s: expected %q to be invalid", format, value)
+			}
+		}
+	}
+}
+
+// restoreFormats puts the format catalog back as it was once t and its subtests complete.
+func restoreFormats(t *testing.T) {
+	formatsMu.RLock()
+	saved := make(map[string]FormatChecker, len(formats))
+	for name, checker := range formats {
+		saved[name] = checker
+	}
+	formatsMu.RUnlock()
+	t.Cleanup(func() {
+		formatsMu.Lock()
+		formats = saved
+		formatsMu.Unlock()
+	})
+}
+
+func TestRegisterFormat(t *testing.T) {
+	restoreFormats(t)
+	if err := RegisterFormat("even-length", func(s string) bool { return len(s)%2 == 0 }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	op, err := Instantiate(Format, "v", "even-length")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"v":"ab"}`)); !res.Match {
+		t.Fatalf("expected custom format to match: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"v":"abc"}`)); res.Match || res.CauseDescription != "value is not a valid even-length" {
+		t.Fatalf("expected custom format to fail: %#v", res)
+	}
+
+	if _, err := Instantiate(Format, "v", "unknown"); err == nil {
+		t.Fatalf("expected unknown format to be rejected")
+	}
+	if err := RegisterFormat("", nil); err == nil {
+		t.Fatalf("expected empty registration to be rejected")
+	}
+}
@@ -0,0 +1,230 @@
+package comparison
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// The checkers below implement the JSON Schema format catalog without regular expressions. Each one
+// scans the input once and never allocates.
+
+// isUUID accepts the 8-4-4-4-12 hexadecimal form of RFC 4122.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(s[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isEmail accepts a dot-atom local part (RFC 5322) and a hostname domain.
+func isEmail(s string) bool {
+	at := strings.LastIndexByte(s, '@')
+	if at <= 0 || at > 64 {
+		return false
+	}
+	local, domain := s[:at], s[at+1:]
+	if local[0] == '.' || local[len(local)-1] == '.' {
+		return false
+	}
+	for i := 0; i < len(local); i++ {
+		c := local[i]
+		switch {
+		case isAlphaNum(c):
+		case c == '.':
+			if local[i-1] == '.' {
+				return false
+			}
+		case strings.IndexByte("!#$%&'*+/=?^_`{|}~-", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return isHostname(domain)
+}
+
+// isURI accepts an absolute URI (RFC 3986): a scheme followed by characters from the URI alphabet
+// with well-formed percent-encodings.
+func isURI(s string) bool {
+	colon := strings.IndexByte(s, ':')
+	if colon <= 0 || !isAlpha(s[0]) {
+		return false
+	}
+	for i := 1; i < colon; i++ {
+		c := s[i]
+		if !isAlphaNum(c) && c != '+' && c != '-' && c != '.' {
+			return false
+		}
+	}
+	rest := s[colon+1:]
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case isAlphaNum(c):
+		case c == '%':
+			if i+2 >= len(rest) || !isHex(rest[i+1]) || !isHex(rest[i+2]) {
+				return false
+			}
+			i += 2
+		case strings.IndexByte("-._~:/?#[]@!$&'()*+,;=", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isIPv4 accepts dotted-quad addresses without leading zeros.
+func isIPv4(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is4()
+}
+
+// isIPv6 accepts RFC 4291 text forms without zone identifiers.
+func isIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is6() && addr.Zone() == ""
+}
+
+// isDate accepts RFC 3339 full-date values with calendar-valid days.
+func isDate(s string) bool {
+	if len(s) != 10 || s[4] != '-' || s[7] != '-' {
+		return false
+	}
+	year, ok1 := digits(s[0:4])
+	month, ok2 := digits(s[5:7])
+	day, ok3 := digits(s[8:10])
+	if !ok1 || !ok2 || !ok3 || month < 1 || month > 12 || day < 1 {
+		return false
+	}
+	return day <= daysIn(month, year)
+}
+
+// isDateTime accepts RFC 3339 date-time values, including leap seconds and lower-case separators.
+func isDateTime(s string) bool {
+	if len(s) < 20 || !isDate(s[:10]) || (s[10] != 'T' && s[10] != 't') {
+		return false
+	}
+	t := s[11:]
+	if t[2] != ':' || t[5] != ':' {
+		return false
+	}
+	hour, ok1 := digits(t[0:2])
+	minute, ok2 := digits(t[3:5])
+	second, ok3 := digits(t[6:8])
+	if !ok1 || !ok2 || !ok3 || hour > 23 || minute > 59 || second > 60 {
+		return false
+	}
+	t = t[8:]
+	if t != "" && t[0] == '.' {
+		i := 1
+		for i < len(t) && isDigit(t[i]) {
+			i++
+		}
+		if i == 1 {
+			return false
+		}
+		t = t[i:]
+	}
+	switch {
+	case t == "Z" || t == "z":
+		return true
+	case len(t) == 6 && (t[0] == '+' || t[0] == '-') && t[3] == ':':
+		offHour, ok1 := digits(t[1:3])
+		offMinute, ok2 := digits(t[4:6])
+		return ok1 && ok2 && offHour <= 23 && offMinute <= 59
+	default:
+		return false
+	}
+}
+
+// isHostname accepts RFC 1123 host names: dot-separated labels of up to 63 letters, digits and inner
+// hyphens, 253 characters in total.
+func isHostname(s string) bool {
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for s != "" {
+		var label string
+		label, s, _ = strings.Cut(s, ".")
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			if !isAlphaNum(label[i]) && label[i] != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isBase64 accepts padded standard base64 (RFC 4648 section 4).
+func isBase64(s string) bool {
+	if len(s)%4 != 0 {
+		return false
+	}
+	padding := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isAlphaNum(c) || c == '+' || c == '/':
+			if padding > 0 {
+				return false
+			}
+		case c == '=' && i >= len(s)-2:
+			padding++
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isAlphaNum(c byte) bool {
+	return isAlpha(c) || isDigit(c)
+}
+
+func digits(s string) (int, bool) {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return 0, false
+		}
+		n = n*10 + int(s[i]-'0')
+	}
+	return n, true
+}
+
+func daysIn(month, year int) int {
+	switch month {
+	case 2:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	default:
+		return 31
+	}
+}
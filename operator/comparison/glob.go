@@ -0,0 +1,248 @@
+package comparison
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/match"
+)
+
+// DefaultGlobComplexity bounds wildcard backtracking per input character, mirroring the limit gjson
+// applies to its own path patterns.
+const DefaultGlobComplexity = 500
+
+// GlobOptions tunes how GlobOperator matches wildcard patterns.
+type GlobOptions struct {
+	// IgnoreCase compares characters under simple Unicode case folding, as ieq does.
+	IgnoreCase bool
+	// MaxComplexity limits matching work to MaxComplexity*len(value) steps. Zero selects DefaultGlobComplexity.
+	MaxComplexity int
+}
+
+// GlobOperator matches the value of a JSON path against a wildcard pattern where `*` matches any
+// sequence of characters, `?` matches a single character and `\` escapes the next character.
+type GlobOperator struct {
+	jsonPath           string
+	pattern            string
+	opts               GlobOptions
+	pathNotFoundMsg    string
+	notStringMsg       string
+	patternMismatchMsg string
+	complexityMsg      string
+}
+
+// NewGlobOperator creates a GlobOperator for the provided wildcard pattern.
+func NewGlobOperator(jsonPath, pattern string, opts GlobOptions) (*GlobOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("glob pattern must not be empty")
+	}
+	if opts.MaxComplexity < 0 {
+		return nil, fmt.Errorf("glob complexity limit must not be negative")
+	}
+	if opts.MaxComplexity == 0 {
+		opts.MaxComplexity = DefaultGlobComplexity
+	}
+	return &GlobOperator{
+		jsonPath:           jsonPath,
+		pattern:            pattern,
+		opts:               opts,
+		pathNotFoundMsg:    "json path " + jsonPath + " not found",
+		notStringMsg:       "value at json path " + jsonPath + " is not a string",
+		patternMismatchMsg: fmt.Sprintf("value does not match pattern %s", pattern),
+		complexityMsg:      fmt.Sprintf("pattern %s exceeded complexity limit %d", pattern, opts.MaxComplexity),
+	}, nil
+}
+
+// MustNewGlobOperator panics if construction fails.
+func MustNewGlobOperator(jsonPath, pattern string, opts GlobOptions) *GlobOperator {
+	op, err := NewGlobOperator(jsonPath, pattern, opts)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *GlobOperator) Name() string {
+	return string(Like)
+}
+
+// Spec describes the operator.
+func (o *GlobOperator) Spec() Spec {
+	var opts []interface{}
+	if o.opts.IgnoreCase {
+		opts = append(opts, "ignoreCase", true)
+	}
+	if o.opts.MaxComplexity != DefaultGlobComplexity {
+		opts = append(opts, "maxComplexity", o.opts.MaxComplexity)
+	}
+	if len(opts) == 0 {
+		return Spec{Type: Like, Field: o.jsonPath, Value: o.pattern}
+	}
+	return Spec{Type: Like, Field: o.jsonPath, Value: optionMap("pattern", o.pattern, opts...)}
+}
+
+// Evaluate executes the wildcard match against the JSON value at jsonPath.
+func (o *GlobOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *GlobOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *GlobOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	if actual.Type != gjson.String {
+		return jsonfilter.ErrorResult(o.Name(), o.notStringMsg)
+	}
+
+	var matched, stopped bool
+	switch {
+	case o.opts.IgnoreCase:
+		matched, stopped = matchFoldLimit(actual.Str, o.pattern, o.opts.MaxComplexity)
+	case actual.Str == "":
+		// MatchLimit grants no steps to an empty string, yet matching one only walks the pattern once.
+		matched = match.Match(actual.Str, o.pattern)
+	default:
+		matched, stopped = match.MatchLimit(actual.Str, o.pattern, o.opts.MaxComplexity)
+	}
+	if stopped {
+		return jsonfilter.EvaluationErrorResult(o.Name(), o.complexityMsg)
+	}
+	if matched {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.patternMismatchMsg)
+}
+
+// Validate re-validates invariant fields.
+func (o *GlobOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if o.pattern == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "glob operator must have a pattern")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+type globResult int
+
+const (
+	globNoMatch globResult = iota
+	globMatch
+	globStopped
+)
+
+// matchFoldLimit is match.MatchLimit with characters compared by equalFoldRune. Like MatchLimit it
+// stops once the wildcard search has taken more than maxComplexity steps per byte of str, on top of
+// one step per pattern byte so that an empty str can still be matched.
+func matchFoldLimit(str, pattern string, maxComplexity int) (matched, stopped bool) {
+	if pattern == "*" {
+		return true, false
+	}
+	steps := 0
+	r := matchFold(str, pattern, len(pattern)+len(str)*maxComplexity, &steps)
+	return r == globMatch, r == globStopped
+}
+
+func matchFold(str, pat string, limit int, steps *int) globResult {
+	if *steps > limit {
+		return globStopped
+	}
+	*steps++
+
+	for pat != "" {
+		pc, pn := utf8.DecodeRuneInString(pat)
+		switch pc {
+		case '*':
+			for len(pat) > 1 && pat[1] == '*' {
+				pat = pat[1:]
+			}
+			if len(pat) == 1 {
+				return globMatch
+			}
+			for {
+				if r := matchFold(str, pat[1:], limit, steps); r != globNoMatch {
+					return r
+				}
+				if str == "" {
+					return globNoMatch
+				}
+				_, sn := utf8.DecodeRuneInString(str)
+				str = str[sn:]
+			}
+		case '?':
+			if str == "" {
+				return globNoMatch
+			}
+		default:
+			if pc == '\\' {
+				pat = pat[pn:]
+				if pat == "" {
+					return globNoMatch
+				}
+				pc, pn = utf8.DecodeRuneInString(pat)
+			}
+			sc, _ := utf8.DecodeRuneInString(str)
+			if str == "" || !equalFoldRune(sc, pc) {
+				return globNoMatch
+			}
+		}
+		_, sn := utf8.DecodeRuneInString(str)
+		str, pat = str[sn:], pat[pn:]
+	}
+	if str == "" {
+		return globMatch
+	}
+	return globNoMatch
+}
+
+// parseGlob accepts either the pattern string or an object with pattern, ignoreCase and maxComplexity.
+func parseGlob(value interface{}) (string, GlobOptions, error) {
+	if pattern, ok := value.(string); ok {
+		return pattern, GlobOptions{}, nil
+	}
+
+	cfg, ok := toStringMap(value)
+	if !ok {
+		return "", GlobOptions{}, fmt.Errorf("like operator expects a string or an object with pattern, got %T", value)
+	}
+
+	var opts GlobOptions
+	pattern, ok := cfg["pattern"].(string)
+	if !ok {
+		return "", GlobOptions{}, fmt.Errorf("like operator expects a string pattern")
+	}
+	for key, raw := range cfg {
+		switch key {
+		case "pattern":
+		case "ignoreCase":
+			flag, ok := raw.(bool)
+			if !ok {
+				return "", GlobOptions{}, fmt.Errorf("like option ignoreCase expects a boolean, got %T", raw)
+			}
+			opts.IgnoreCase = flag
+		case "maxComplexity":
+			limit, ok := ToInt(raw)
+			if !ok || limit <= 0 {
+				return "", GlobOptions{}, fmt.Errorf("like option maxComplexity expects a positive integer, got %v", raw)
+			}
+			opts.MaxComplexity = limit
+		default:
+			return "", GlobOptions{}, fmt.Errorf("like operator does not support option %q", key)
+		}
+	}
+	return pattern, opts, nil
+}
@@ -3,6 +3,7 @@ package comparison
 import (
 	"unsafe"
 
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
 	"github.com/tidwall/gjson"
 )
 
@@ -17,3 +18,10 @@ func getJSONResult(payload []byte, path string) gjson.Result {
 	jsonStr := *(*string)(unsafe.Pointer(&payload))
 	return gjson.Get(jsonStr, path)
 }
+
+// getContextResult resolves a path against the documents of a context. Paths prefixed with `@name.`
+// select a document other than the body.
+func getContextResult(ctx *jsonfilter.Context, path string) gjson.Result {
+	doc, docPath := ctx.Resolve(path)
+	return getJSONResult(doc, docPath)
+}
@@ -0,0 +1,239 @@
+package comparison
+
+import (
+	"fmt"
+	"strings"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// MembershipOperator implements in and nin. A scalar value is a member when it equals one of the
+// listed values under eq semantics; an array value is a member when any of its elements is.
+type MembershipOperator struct {
+	typ             Type
+	jsonPath        string
+	values          []interface{}
+	members         []*EqualOperator
+	pathNotFoundMsg string
+	mismatchMsg     string
+}
+
+// NewMembershipOperator constructs an In or NotIn operator.
+func NewMembershipOperator(t Type, jsonPath string, values []interface{}) (*MembershipOperator, error) {
+	if t != In && t != NotIn {
+		return nil, fmt.Errorf("comparison operator %s is not a membership operator", t)
+	}
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s operator requires at least one value", t)
+	}
+	op := &MembershipOperator{
+		typ:             t,
+		jsonPath:        jsonPath,
+		values:          values,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+	}
+	for _, value := range values {
+		member, err := NewEqualOperator(jsonPath, value)
+		if err != nil {
+			return nil, err
+		}
+		op.members = append(op.members, member)
+	}
+	if t == In {
+		op.mismatchMsg = fmt.Sprintf("value is not one of %v", values)
+	} else {
+		op.mismatchMsg = fmt.Sprintf("value is one of %v", values)
+	}
+	return op, nil
+}
+
+// MustNewMembershipOperator panics when inputs are invalid.
+func MustNewMembershipOperator(t Type, jsonPath string, values []interface{}) *MembershipOperator {
+	op, err := NewMembershipOperator(t, jsonPath, values)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *MembershipOperator) Name() string {
+	return string(o.typ)
+}
+
+// Spec describes the operator.
+func (o *MembershipOperator) Spec() Spec {
+	return Spec{Type: o.typ, Field: o.jsonPath, Value: o.values}
+}
+
+// Evaluate checks whether the value at jsonPath is one of the listed values.
+func (o *MembershipOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *MembershipOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *MembershipOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	found := false
+	if actual.IsArray() {
+		actual.ForEach(func(_, element gjson.Result) bool {
+			found = o.isMember(element)
+			return !found
+		})
+	} else {
+		found = o.isMember(actual)
+	}
+
+	if found == (o.typ == In) {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+func (o *MembershipOperator) isMember(actual gjson.Result) bool {
+	for _, member := range o.members {
+		if member.matches(actual) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *MembershipOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if len(o.members) == 0 {
+		return jsonfilter.ErrorValidationResult(o.Name(), "membership operator requires at least one value")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// ContainsOperator implements ct and nct: a string contains the expected substring, or an array
+// contains an element equal to the expected value.
+type ContainsOperator struct {
+	typ             Type
+	jsonPath        string
+	element         *EqualOperator
+	substring       string
+	isString        bool
+	pathNotFoundMsg string
+	unsupportedMsg  string
+	mismatchMsg     string
+}
+
+// NewContainsOperator constructs a Contains or NotContains operator.
+func NewContainsOperator(t Type, jsonPath string, expected interface{}) (*ContainsOperator, error) {
+	if t != Contains && t != NotContains {
+		return nil, fmt.Errorf("comparison operator %s is not a contains operator", t)
+	}
+	element, err := NewEqualOperator(jsonPath, expected)
+	if err != nil {
+		return nil, err
+	}
+	op := &ContainsOperator{
+		typ:             t,
+		jsonPath:        jsonPath,
+		element:         element,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		unsupportedMsg:  "value at json path " + jsonPath + " is not a string or array",
+	}
+	op.substring, op.isString = expected.(string)
+	if t == Contains {
+		op.mismatchMsg = fmt.Sprintf("value does not contain %v", expected)
+	} else {
+		op.mismatchMsg = fmt.Sprintf("value contains %v", expected)
+	}
+	return op, nil
+}
+
+// MustNewContainsOperator panics when inputs are invalid.
+func MustNewContainsOperator(t Type, jsonPath string, expected interface{}) *ContainsOperator {
+	op, err := NewContainsOperator(t, jsonPath, expected)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *ContainsOperator) Name() string {
+	return string(o.typ)
+}
+
+// Spec describes the operator.
+func (o *ContainsOperator) Spec() Spec {
+	return Spec{Type: o.typ, Field: o.jsonPath, Value: o.element.Spec().Value}
+}
+
+// Evaluate checks whether the value at jsonPath contains the expected value.
+func (o *ContainsOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *ContainsOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *ContainsOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	var found bool
+	switch {
+	case actual.IsArray():
+		actual.ForEach(func(_, element gjson.Result) bool {
+			found = o.element.matches(element)
+			return !found
+		})
+	case actual.Type == gjson.String && o.isString:
+		found = strings.Contains(actual.Str, o.substring)
+	default:
+		return jsonfilter.ErrorResult(o.Name(), o.unsupportedMsg)
+	}
+
+	if found == (o.typ == Contains) {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *ContainsOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// parseValueList accepts a list of literals for in and nin.
+func parseValueList(t Type, value interface{}) ([]interface{}, error) {
+	switch typed := value.(type) {
+	case []interface{}:
+		return typed, nil
+	case []string:
+		values := make([]interface{}, len(typed))
+		for i, item := range typed {
+			values[i] = item
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("%s operator expects a list of values, got %T", t, value)
+	}
+}
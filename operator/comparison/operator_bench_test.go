@@ -1,6 +1,11 @@
 package comparison
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+)
 
 func BenchmarkEqualOperatorEvaluateMatch(b *testing.B) {
 	op := MustNewEqualOperator("foo", "bar")
@@ -40,3 +45,222 @@ func BenchmarkRegexOperatorEvaluate(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkSizeOperatorEvaluate(b *testing.B) {
+	op := MustNewSizeOperator("items", SizeBounds{Min: 1, Max: 50})
+	payload := []byte(`{"items":[1,2,3,4,5]}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected size match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkStartsWithOperatorEvaluate(b *testing.B) {
+	op := MustNewStringOperator(StartsWith, "foo", "ba", StringOptions{})
+	payload := []byte(`{"foo":"barbaz"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected prefix match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkRegexPrefixEvaluate(b *testing.B) {
+	op := MustNewRegexOperator("foo", `^ba`)
+	payload := []byte(`{"foo":"barbaz"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected regex prefix match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkEndsWithOperatorEvaluate(b *testing.B) {
+	op := MustNewStringOperator(EndsWith, "foo", "baz", StringOptions{})
+	payload := []byte(`{"foo":"barbaz"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected suffix match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkRegexSuffixEvaluate(b *testing.B) {
+	op := MustNewRegexOperator("foo", `baz$`)
+	payload := []byte(`{"foo":"barbaz"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected regex suffix match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkEqualFoldOperatorEvaluate(b *testing.B) {
+	op := MustNewStringOperator(EqualFold, "foo", "BARBAZ", StringOptions{})
+	payload := []byte(`{"foo":"barbaz"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected case-insensitive match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkRegexEqualFoldEvaluate(b *testing.B) {
+	op := MustNewRegexOperator("foo", `(?i)^barbaz$`)
+	payload := []byte(`{"foo":"barbaz"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected regex case-insensitive match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkGlobOperatorEvaluate(b *testing.B) {
+	op := MustNewGlobOperator("foo", "ba*z", GlobOptions{})
+	payload := []byte(`{"foo":"barbaz"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected glob match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkSemverOperatorEvaluate(b *testing.B) {
+	op := MustNewSemverOperator("version", ">=2.3.0 <3.0.0 || ^4.1")
+	payload := []byte(`{"version":"4.2.0-beta.3"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected semver match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkCIDROperatorEvaluate(b *testing.B) {
+	prefixes := make([]string, 0, 1024)
+	for i := 0; i < 1024; i++ {
+		prefixes = append(prefixes, fmt.Sprintf("10.%d.%d.0/24", i/256, i%256*2%256))
+	}
+	op := MustNewCIDROperator("ip", prefixes)
+	payload := []byte(`{"ip":"10.3.254.17"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected cidr match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkBetweenOperatorEvaluate(b *testing.B) {
+	op := MustNewBetweenOperator("amount", BetweenBounds{Min: "0.01", Max: "10000.00"})
+	payload := []byte(`{"amount":1234.56}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected between match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkFormatUUIDEvaluate(b *testing.B) {
+	op := MustNewFormatOperator("id", "uuid")
+	payload := []byte(`{"id":"123e4567-e89b-12d3-a456-426614174000"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected uuid match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkRegexUUIDEvaluate(b *testing.B) {
+	op := MustNewRegexOperator("id", `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	payload := []byte(`{"id":"123e4567-e89b-12d3-a456-426614174000"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected regex uuid match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkSchemaOperatorEvaluate(b *testing.B) {
+	op := MustNewSchemaOperator("order", map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"id"},
+		"properties": map[string]interface{}{
+			"id":    map[string]interface{}{"type": "string", "minLength": 3},
+			"items": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}},
+		},
+	})
+	payload := []byte(`{"order":{"id":"ABC-1","items":[1,2,3]}}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected schema match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkEqualOperatorEvaluateContext(b *testing.B) {
+	op := MustNewEqualOperator("@headers.x-tenant", "acme")
+	ctx := jsonfilter.NewContext([]byte(`{"id":1}`)).With("headers", []byte(`{"x-tenant":"acme"}`))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := jsonfilter.EvaluateContext(op, ctx); !res.Match {
+			b.Fatalf("expected context match, got %#v", res)
+		}
+	}
+}
+
+func BenchmarkGlobOperatorEvaluateIgnoreCase(b *testing.B) {
+	op := MustNewGlobOperator("foo", "BA*Z", GlobOptions{IgnoreCase: true})
+	payload := []byte(`{"foo":"barbaz"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected glob match, got %#v", res)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package comparison
 
-import "testing"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+)
 
 func TestEqualOperatorMatch(t *testing.T) {
 	op := MustNewEqualOperator("foo", "bar")
@@ -27,3 +34,584 @@ func TestRegexOperatorEvaluate(t *testing.T) {
 		t.Fatalf("expected regex to fail: %#v", res)
 	}
 }
+
+func TestSizeOperatorBounds(t *testing.T) {
+	op := MustNewSizeOperator("items", SizeBounds{Min: 1, Max: 3})
+	cases := []struct {
+		payload string
+		match   bool
+		cause   string
+	}{
+		{`{"items":[1,2]}`, true, ""},
+		{`{"items":[]}`, false, "size 0 is below minimum 1"},
+		{`{"items":[1,2,3,4]}`, false, "size 4 exceeds maximum 3"},
+		{`{"items":{"a":1,"b":2}}`, true, ""},
+		{`{"items":"héé"}`, true, ""},
+		{`{"items":42}`, false, "value at json path items is not an array, object or string"},
+		{`{}`, false, "json path items not found"},
+	}
+	for _, tc := range cases {
+		res := op.Evaluate([]byte(tc.payload))
+		if res.Match != tc.match || res.CauseDescription != tc.cause {
+			t.Fatalf("payload %s: unexpected result %#v", tc.payload, res)
+		}
+	}
+}
+
+func TestSizeOperatorInstantiate(t *testing.T) {
+	op, err := Instantiate(MustParseType("len"), "name", map[string]interface{}{"eq": 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"name":"abc"}`)); res.Match || res.CauseDescription != "size 3 does not equal expected 2" {
+		t.Fatalf("unexpected result %#v", res)
+	}
+	if _, err := Instantiate(Size, "name", map[string]interface{}{"min": 5, "max": 1}); err == nil {
+		t.Fatalf("expected inverted bounds to be rejected")
+	}
+	if _, err := Instantiate(Size, "name", map[string]interface{}{"eq": 1, "max": 1}); err == nil {
+		t.Fatalf("expected eq combined with max to be rejected")
+	}
+}
+
+func TestNotEqualOperator(t *testing.T) {
+	op := MustNewNotEqualOperator("foo", "bar")
+	if res := op.Evaluate([]byte(`{"foo":"baz"}`)); !res.Match {
+		t.Fatalf("expected ne to match: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"foo":"bar"}`)); res.Match || res.CauseDescription != "value equals bar" {
+		t.Fatalf("expected ne to fail: %#v", res)
+	}
+}
+
+func TestOrderingOperator(t *testing.T) {
+	cases := []struct {
+		typ      Type
+		expected interface{}
+		payload  string
+		match    bool
+	}{
+		{LessThan, 10, `{"v":9.5}`, true},
+		{LessThan, 10, `{"v":10}`, false},
+		{LessEqual, 10, `{"v":10}`, true},
+		{GreaterThan, 1.5, `{"v":2}`, true},
+		{GreaterEqual, "2024-01-01", `{"v":"2024-03-01"}`, true},
+		{GreaterEqual, "2024-01-01", `{"v":"2023-12-31"}`, false},
+		{GreaterThan, 1, `{"v":"2"}`, false},
+	}
+	for _, tc := range cases {
+		op := MustNewOrderingOperator(tc.typ, "v", tc.expected)
+		if res := op.Evaluate([]byte(tc.payload)); res.Match != tc.match {
+			t.Fatalf("%s %v on %s: unexpected result %#v", tc.typ, tc.expected, tc.payload, res)
+		}
+	}
+	if _, err := NewOrderingOperator(LessThan, "v", true); err == nil {
+		t.Fatalf("expected boolean ordering literal to be rejected")
+	}
+}
+
+func TestReferenceOperator(t *testing.T) {
+	payload := []byte(`{"billing":{"country":"DE"},"shipping":{"country":"FR"},"startDate":"2024-01-01","endDate":"2024-02-01"}`)
+
+	eq := MustNewReferenceOperator(Equal, "billing.country", "shipping.country")
+	res := eq.Evaluate(payload)
+	if res.Match {
+		t.Fatalf("expected countries to differ: %#v", res)
+	}
+	if want := `value "DE" at billing.country did not equal value "FR" at shipping.country`; res.CauseDescription != want {
+		t.Fatalf("unexpected cause %q", res.CauseDescription)
+	}
+
+	if res := MustNewReferenceOperator(NotEqual, "billing.country", "shipping.country").Evaluate(payload); !res.Match {
+		t.Fatalf("expected ne to match: %#v", res)
+	}
+	if res := MustNewReferenceOperator(GreaterThan, "endDate", "startDate").Evaluate(payload); !res.Match {
+		t.Fatalf("expected endDate > startDate: %#v", res)
+	}
+	if res := MustNewReferenceOperator(Equal, "billing.country", "missing").Evaluate(payload); res.Match || res.CauseDescription != "reference json path missing not found" {
+		t.Fatalf("expected missing reference: %#v", res)
+	}
+	if _, err := NewReferenceOperator(Regex, "a", "b"); err == nil {
+		t.Fatalf("expected rx to reject valueFrom")
+	}
+}
+
+func TestStringOperatorPredicates(t *testing.T) {
+	cases := []struct {
+		typ      Type
+		expected string
+		opts     StringOptions
+		value    string
+		match    bool
+	}{
+		{StartsWith, "ABC-", StringOptions{}, "ABC-1234", true},
+		{StartsWith, "abc-", StringOptions{}, "ABC-1234", false},
+		{StartsWith, "abc-", StringOptions{IgnoreCase: true}, "ABC-1234", true},
+		{EndsWith, ".json", StringOptions{}, "payload.json", true},
+		{EndsWith, ".JSON", StringOptions{IgnoreCase: true}, "payload.json", true},
+		{EndsWith, ".json", StringOptions{}, "payload.yaml", false},
+		{EqualFold, "straße", StringOptions{}, "STRASSE", false},
+		{EqualFold, "ΣΊΣΥΦΟΣ", StringOptions{}, "σίσυφος", true},
+		{StartsWith, "ſ", StringOptions{IgnoreCase: true}, "Sun", true},
+		{EqualFold, "Café", StringOptions{NormalizeNFC: true}, "CAFÉ", true},
+		{EqualFold, "Café", StringOptions{}, "CAFÉ", false},
+	}
+	for _, tc := range cases {
+		op := MustNewStringOperator(tc.typ, "v", tc.expected, tc.opts)
+		payload := []byte(`{"v":"` + tc.value + `"}`)
+		if res := op.Evaluate(payload); res.Match != tc.match {
+			t.Fatalf("%s %q on %q: unexpected result %#v", tc.typ, tc.expected, tc.value, res)
+		}
+	}
+}
+
+func TestStringOperatorInstantiate(t *testing.T) {
+	op, err := Instantiate(StartsWith, "v", map[string]interface{}{"value": "ab", "ignoreCase": true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"v":"ABC"}`)); !res.Match {
+		t.Fatalf("expected case-insensitive prefix match: %#v", res)
+	}
+	if _, err := Instantiate(EndsWith, "v", map[string]interface{}{"value": "ab", "normalize": "nfd"}); err == nil {
+		t.Fatalf("expected unsupported normalization form to be rejected")
+	}
+}
+
+func TestGlobOperator(t *testing.T) {
+	op := MustNewGlobOperator("host", "api-*.example.??", GlobOptions{})
+	if res := op.Evaluate([]byte(`{"host":"api-eu.example.de"}`)); !res.Match {
+		t.Fatalf("expected glob match: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"host":"API-eu.example.de"}`)); res.Match {
+		t.Fatalf("expected case-sensitive glob to fail: %#v", res)
+	}
+
+	insensitive := MustNewGlobOperator("host", "API-*", GlobOptions{IgnoreCase: true})
+	if res := insensitive.Evaluate([]byte(`{"host":"api-eu"}`)); !res.Match {
+		t.Fatalf("expected case-insensitive glob match: %#v", res)
+	}
+
+	if res := insensitive.Evaluate([]byte(`{"host":"ＡＰＩ-eu"}`)); res.Match {
+		t.Fatalf("expected width variants not to fold: %#v", res)
+	}
+	greek := MustNewGlobOperator("v", "*σ", GlobOptions{IgnoreCase: true})
+	if res := greek.Evaluate([]byte(`{"v":"ΛΌΓΟΣ"}`)); !res.Match {
+		t.Fatalf("expected case folding to match ieq: %#v", res)
+	}
+	if spec := insensitive.Spec(); spec.Value.(map[string]interface{})["pattern"] != "API-*" {
+		t.Fatalf("expected the pattern as written: %#v", spec)
+	}
+
+	wildcard := MustNewGlobOperator("v", "*", GlobOptions{})
+	for _, payload := range []string{`{"v":5}`, `{"v":true}`, `{"v":null}`, `{"v":{}}`} {
+		if res := wildcard.Evaluate([]byte(payload)); res.Match || res.CauseDescription != "value at json path v is not a string" {
+			t.Fatalf("expected %s not to match a glob: %#v", payload, res)
+		}
+	}
+
+	escaped := MustNewGlobOperator("v", `what\?`, GlobOptions{})
+	if res := escaped.Evaluate([]byte(`{"v":"whats"}`)); res.Match {
+		t.Fatalf("expected escaped wildcard to be literal: %#v", res)
+	}
+}
+
+func TestGlobOperatorComplexityLimit(t *testing.T) {
+	op := MustNewGlobOperator("v", "*a*a*a*a*a*a*a*a*b*", GlobOptions{MaxComplexity: 1})
+	folded := MustNewGlobOperator("v", "*a*a*a*a*a*a*a*a*b*", GlobOptions{MaxComplexity: 1, IgnoreCase: true})
+	if res := folded.Evaluate([]byte(`{"v":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}`)); res.Outcome() != jsonfilter.OutcomeError {
+		t.Fatalf("expected the case-insensitive search to respect the limit: %#v", res)
+	}
+	res := op.Evaluate([]byte(`{"v":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}`))
+	if res.Outcome() != jsonfilter.OutcomeError || res.CauseDescription != "pattern *a*a*a*a*a*a*a*a*b* exceeded complexity limit 1" {
+		t.Fatalf("expected complexity limit to stop matching with an error: %#v", res)
+	}
+
+	for _, o := range []*GlobOperator{op, folded, MustNewGlobOperator("v", "*x*", GlobOptions{MaxComplexity: 1})} {
+		if res := o.Evaluate([]byte(`{"v":""}`)); res.Outcome() != jsonfilter.OutcomeNoMatch {
+			t.Fatalf("expected an empty value to be a plain non-match: %#v", res)
+		}
+	}
+	if res := MustNewGlobOperator("v", "**", GlobOptions{MaxComplexity: 1, IgnoreCase: true}).Evaluate([]byte(`{"v":""}`)); !res.Match {
+		t.Fatalf("expected stars to match an empty value: %#v", res)
+	}
+
+	if _, err := Instantiate(MustParseType("glob"), "v", map[string]interface{}{"pattern": "a*", "maxComplexity": 0}); err == nil {
+		t.Fatalf("expected non-positive complexity to be rejected")
+	}
+}
+
+func TestEqualOperatorExactNumbers(t *testing.T) {
+	big := MustNewEqualOperator("id", json.Number("9007199254740993"))
+	if res := big.Evaluate([]byte(`{"id":9007199254740993}`)); !res.Match {
+		t.Fatalf("expected big integer to match exactly: %#v", res)
+	}
+	if res := big.Evaluate([]byte(`{"id":9007199254740992}`)); res.Match {
+		t.Fatalf("expected neighbouring big integer not to match: %#v", res)
+	}
+
+	point := MustNewEqualOperator("v", 0.3)
+	if res := point.Evaluate([]byte(`{"v":0.30}`)); !res.Match {
+		t.Fatalf("expected 0.30 to equal 0.3: %#v", res)
+	}
+	if res := point.Evaluate([]byte(`{"v":0.30000000000000004}`)); res.Match {
+		t.Fatalf("expected 0.1+0.2 not to equal 0.3 exactly: %#v", res)
+	}
+
+	whole := MustNewEqualOperator("v", 5)
+	if res := whole.Evaluate([]byte(`{"v":5.5}`)); res.Match {
+		t.Fatalf("expected 5.5 not to equal 5: %#v", res)
+	}
+	if res := whole.Evaluate([]byte(`{"v":"5"}`)); !res.Match {
+		t.Fatalf("expected numeric string to equal 5: %#v", res)
+	}
+}
+
+func TestBetweenOperator(t *testing.T) {
+	op := MustNewBetweenOperator("amount", BetweenBounds{Min: "10.00", Max: json.Number("99999999999999999999.99"), ExclusiveMin: true})
+	cases := []struct {
+		payload string
+		match   bool
+	}{
+		{`{"amount":10.01}`, true},
+		{`{"amount":10}`, false},
+		{`{"amount":99999999999999999999.99}`, true},
+		{`{"amount":99999999999999999999.991}`, false},
+		{`{"amount":"50"}`, false},
+	}
+	for _, tc := range cases {
+		if res := op.Evaluate([]byte(tc.payload)); res.Match != tc.match {
+			t.Fatalf("%s: unexpected result %#v", tc.payload, res)
+		}
+	}
+	if res := op.Evaluate([]byte(`{"amount":5}`)); res.CauseDescription != "value is not within (10.00, 99999999999999999999.99]" {
+		t.Fatalf("unexpected cause %q", res.CauseDescription)
+	}
+
+	if _, err := Instantiate(Between, "v", map[string]interface{}{"min": 5, "max": 1}); err == nil {
+		t.Fatalf("expected inverted bounds to be rejected")
+	}
+	if _, err := Instantiate(Between, "v", map[string]interface{}{"min": "abc", "max": 1}); err == nil {
+		t.Fatalf("expected non-numeric bound to be rejected")
+	}
+}
+
+func TestApproxOperator(t *testing.T) {
+	op, err := Instantiate(Approx, "v", 0.3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"v":0.30000000000000004}`)); !res.Match {
+		t.Fatalf("expected 0.1+0.2 to approximate 0.3: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"v":0.31}`)); res.Match {
+		t.Fatalf("expected 0.31 not to approximate 0.3: %#v", res)
+	}
+
+	relative := MustNewApproxOperator("v", 1000, ApproxTolerance{Relative: 0.01})
+	if res := relative.Evaluate([]byte(`{"v":1009}`)); !res.Match {
+		t.Fatalf("expected 1009 within 1%% of 1000: %#v", res)
+	}
+	if res := relative.Evaluate([]byte(`{"v":1011}`)); res.Match {
+		t.Fatalf("expected 1011 outside 1%% of 1000: %#v", res)
+	}
+
+	if _, err := Instantiate(Approx, "v", map[string]interface{}{"value": 1, "abs": -1}); err == nil {
+		t.Fatalf("expected negative tolerance to be rejected")
+	}
+}
+
+func TestOperatorsEvaluateContext(t *testing.T) {
+	ctx := jsonfilter.NewContext([]byte(`{"amount":250,"tenant":"acme"}`)).
+		With("headers", []byte(`{"x-tenant":"acme","method":"POST"}`)).
+		With("env", []byte(`{"maxAmount":200}`))
+
+	tenant := MustNewEqualOperator("@headers.x-tenant", "acme")
+	if res := jsonfilter.EvaluateContext(tenant, ctx); !res.Match {
+		t.Fatalf("expected header lookup to match: %#v", res)
+	}
+	if res := tenant.Evaluate(ctx.Body()); res.Match {
+		t.Fatalf("expected plain evaluation not to see context documents: %#v", res)
+	}
+
+	body := MustNewEqualOperator("tenant", "acme")
+	if res := jsonfilter.EvaluateContext(body, ctx); !res.Match {
+		t.Fatalf("expected unprefixed path to address the body: %#v", res)
+	}
+	explicitBody := MustNewEqualOperator("@body.tenant", "acme")
+	if res := jsonfilter.EvaluateContext(explicitBody, ctx); !res.Match {
+		t.Fatalf("expected @body prefix to address the body: %#v", res)
+	}
+
+	limit, err := NewReferenceOperator(LessEqual, "amount", "@env.maxAmount")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res := jsonfilter.EvaluateContext(limit, ctx)
+	if res.Match {
+		t.Fatalf("expected amount above context limit not to match: %#v", res)
+	}
+
+	missing := MustNewEqualOperator("@query.page", "1")
+	if res := jsonfilter.EvaluateContext(missing, ctx); res.Match {
+		t.Fatalf("expected unknown document not to match: %#v", res)
+	}
+}
+
+func TestSpecRoundTrip(t *testing.T) {
+	cases := []struct {
+		typ   Type
+		value interface{}
+	}{
+		{Equal, "done"},
+		{NotEqual, 3},
+		{GreaterEqual, json.Number("100.50")},
+		{Regex, "^ABC"},
+		{StartsWith, map[string]interface{}{"value": "ab", "ignoreCase": true}},
+		{EqualFold, "Done"},
+		{Like, "*.example.com"},
+		{Like, map[string]interface{}{"pattern": "a*", "maxComplexity": 10}},
+		{Semver, "^2.3"},
+		{Before, "now-15m"},
+		{Within, map[string]interface{}{"from": "2024-01-01T00:00:00Z", "epoch": "ms"}},
+		{CIDR, []interface{}{"10.0.0.0/8"}},
+		{Between, map[string]interface{}{"min": 1, "max": 5, "exclusiveMax": true}},
+		{Approx, map[string]interface{}{"value": 0.3, "rel": 0.001}},
+		{Format, "uuid"},
+		{Schema, map[string]interface{}{"type": "string"}},
+		{Size, map[string]interface{}{"min": 1}},
+		{In, []interface{}{"a", 1}},
+		{NotContains, "x"},
+		{Exists, false},
+		{JSONType, []interface{}{"string", "null"}},
+	}
+	for _, tc := range cases {
+		op, err := Instantiate(tc.typ, "field", tc.value)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.typ, err)
+		}
+		spec := op.(Describer).Spec()
+		if spec.Type != tc.typ || spec.Field != "field" {
+			t.Fatalf("%s: unexpected spec %#v", tc.typ, spec)
+		}
+		rebuilt, err := Instantiate(spec.Type, spec.Field, spec.Value)
+		if err != nil {
+			t.Fatalf("%s: spec value %#v does not instantiate: %v", tc.typ, spec.Value, err)
+		}
+		if again := rebuilt.(Describer).Spec(); fmt.Sprint(again) != fmt.Sprint(spec) {
+			t.Fatalf("%s: spec changed on round trip: %#v vs %#v", tc.typ, spec, again)
+		}
+	}
+
+	ref, err := InstantiateReference(LessEqual, "amount", "limit")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec := ref.(Describer).Spec(); spec.ValueFrom != "limit" || spec.Type != LessEqual {
+		t.Fatalf("unexpected reference spec %#v", spec)
+	}
+}
+
+func TestMembershipOperators(t *testing.T) {
+	in := MustNewMembershipOperator(In, "size", []interface{}{"S", json.Number("2")})
+	nin := MustNewMembershipOperator(NotIn, "size", []interface{}{"S", json.Number("2")})
+	cases := map[string]bool{
+		`{"size":"S"}`:        true,
+		`{"size":2.0}`:        true,
+		`{"size":["XL","S"]}`: true,
+		`{"size":"M"}`:        false,
+		`{"size":["M"]}`:      false,
+	}
+	for payload, want := range cases {
+		if res := in.Evaluate([]byte(payload)); res.Match != want {
+			t.Fatalf("in %s: expected %v, got %#v", payload, want, res)
+		}
+		if res := nin.Evaluate([]byte(payload)); res.Match == want {
+			t.Fatalf("nin %s: expected %v, got %#v", payload, !want, res)
+		}
+	}
+	if res := in.Evaluate([]byte(`{}`)); res.Match || res.CauseDescription != "json path size not found" {
+		t.Fatalf("unexpected result %#v", res)
+	}
+	if _, err := Instantiate(In, "size", "S"); err == nil {
+		t.Fatalf("expected scalar in value to be rejected")
+	}
+
+	ct := MustNewContainsOperator(Contains, "tags", "red")
+	if res := ct.Evaluate([]byte(`{"tags":["blue","red"]}`)); !res.Match {
+		t.Fatalf("expected array to contain element: %#v", res)
+	}
+	if res := ct.Evaluate([]byte(`{"tags":"dark red"}`)); !res.Match {
+		t.Fatalf("expected string to contain substring: %#v", res)
+	}
+	if res := ct.Evaluate([]byte(`{"tags":42}`)); res.Match || res.CauseDescription != "value at json path tags is not a string or array" {
+		t.Fatalf("unexpected result %#v", res)
+	}
+	nct := MustNewContainsOperator(NotContains, "tags", "red")
+	if res := nct.Evaluate([]byte(`{"tags":["blue"]}`)); !res.Match {
+		t.Fatalf("expected nct to match: %#v", res)
+	}
+}
+
+func TestExistsAndTypeOperators(t *testing.T) {
+	exists := MustNewExistsOperator("a.b", true)
+	missing := MustNewExistsOperator("a.b", false)
+	if !exists.Evaluate([]byte(`{"a":{"b":null}}`)).Match || missing.Evaluate([]byte(`{"a":{"b":null}}`)).Match {
+		t.Fatalf("expected null value to count as present")
+	}
+	if res := missing.Evaluate([]byte(`{"a":{}}`)); !res.Match {
+		t.Fatalf("expected absent path to match: %#v", res)
+	}
+
+	typ := MustNewTypeOperator("v", TypeInteger, TypeArray)
+	cases := map[string]bool{
+		`{"v":3}`:   true,
+		`{"v":3.0}`: true,
+		`{"v":3.5}`: false,
+		`{"v":[]}`:  true,
+		`{"v":"3"}`: false,
+	}
+	for payload, want := range cases {
+		if res := typ.Evaluate([]byte(payload)); res.Match != want {
+			t.Fatalf("%s: expected %v, got %#v", payload, want, res)
+		}
+	}
+	if _, err := NewTypeOperator("v", "date"); err == nil {
+		t.Fatalf("expected unknown type to be rejected")
+	}
+	if _, err := Instantiate(Exists, "v", "yes"); err == nil {
+		t.Fatalf("expected non-boolean exists value to be rejected")
+	}
+}
+
+func TestTextOperator(t *testing.T) {
+	eq := MustNewTextOperator(Equal, "v", json.Number("1"))
+	rx := MustNewTextOperator(Regex, "v", "[0-9]|x")
+	cases := []struct {
+		payload string
+		eq, rx  bool
+	}{
+		{`{"v":1}`, true, true},
+		{`{"v":"1"}`, true, true},
+		{`{"v":1.0}`, false, false},
+		{`{"v":"x1"}`, false, false},
+		{`{"v":"x"}`, false, true},
+	}
+	for _, tc := range cases {
+		if res := eq.Evaluate([]byte(tc.payload)); res.Match != tc.eq {
+			t.Fatalf("eq %s: expected %v, got %#v", tc.payload, tc.eq, res)
+		}
+		if res := rx.Evaluate([]byte(tc.payload)); res.Match != tc.rx {
+			t.Fatalf("rx %s: expected %v, got %#v", tc.payload, tc.rx, res)
+		}
+	}
+
+	op, err := Instantiate(NotEqual, "v", true, WithTextualValues())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"v":"true"}`)); res.Match {
+		t.Fatalf("expected textual ne to treat \"true\" as true: %#v", res)
+	}
+	if _, err := NewTextOperator(LessThan, "v", 1); err == nil {
+		t.Fatalf("expected lt to be rejected")
+	}
+}
+
+func TestExplainCauses(t *testing.T) {
+	cases := []struct {
+		op       jsonfilter.Operator
+		payload  string
+		code     jsonfilter.CauseCode
+		expected interface{}
+		actual   interface{}
+	}{
+		{MustNewEqualOperator("a", "bar"), `{"a":"baz"}`, jsonfilter.CauseMismatch, "bar", "baz"},
+		{MustNewEqualOperator("a", "bar"), `{}`, jsonfilter.CausePathNotFound, "bar", nil},
+		{MustNewOrderingOperator(LessThan, "a", json.Number("10")), `{"a":12345678901234567890}`, jsonfilter.CauseMismatch, json.Number("10"), json.Number("12345678901234567890")},
+		{MustNewOrderingOperator(GreaterThan, "a", json.Number("10")), `{"a":"11"}`, jsonfilter.CauseTypeMismatch, json.Number("10"), "11"},
+		{MustNewRegexOperator("a", "^x"), `{"a":{"b":[1]}}`, jsonfilter.CauseTypeMismatch, "^x", map[string]interface{}{"b": []interface{}{json.Number("1")}}},
+		{MustNewReferenceOperator(LessThan, "a", "b"), `{"a":3,"b":2}`, jsonfilter.CauseMismatch, json.Number("2"), json.Number("3")},
+	}
+	for _, tc := range cases {
+		res := jsonfilter.Explain(tc.op, []byte(tc.payload))
+		if res.Match || res.Cause == nil {
+			t.Fatalf("%s %s: expected explained mismatch, got %#v", tc.op.Name(), tc.payload, res)
+		}
+		if res.Cause.Code != tc.code || res.Cause.Path != "a" || fmt.Sprint(res.Cause.Expected) != fmt.Sprint(tc.expected) {
+			t.Fatalf("%s %s: unexpected cause %#v", tc.op.Name(), tc.payload, res.Cause)
+		}
+		if tc.actual != nil && fmt.Sprint(res.Cause.Actual) != fmt.Sprint(tc.actual) {
+			t.Fatalf("%s %s: expected actual %v, got %#v", tc.op.Name(), tc.payload, tc.actual, res.Cause.Actual)
+		}
+	}
+
+	op := MustNewEqualOperator("a", "bar")
+	if res := op.Evaluate([]byte(`{"a":"baz"}`)); res.Cause != nil {
+		t.Fatalf("expected plain evaluation without cause, got %#v", res.Cause)
+	}
+	if res := jsonfilter.EvaluateContext(op, jsonfilter.NewContext([]byte(`{"a":"baz"}`))); res.Cause != nil {
+		t.Fatalf("expected context evaluation without cause, got %#v", res.Cause)
+	}
+	if res := jsonfilter.Explain(MustNewReferenceOperator(Equal, "a", "b"), []byte(`{"a":1}`)); res.Cause == nil || res.Cause.Code != jsonfilter.CausePathNotFound || res.Cause.Path != "b" {
+		t.Fatalf("expected missing reference to be reported, got %#v", res.Cause)
+	}
+}
+
+func TestOperatorsRejectPathSyntax(t *testing.T) {
+	valid := []string{
+		"a", "a.b", "a.#", "a.0", `a\.b`, "a|@reverse", "@this", "..0.a",
+		`items.#(name=="x.y")#.id`, `items.#(tags.#(=="a"))`, "[a,b]", "{a,b.c}", `@this:{"k":"a.b"}`,
+	}
+	for _, path := range valid {
+		if _, err := NewEqualOperator(path, 1); err != nil {
+			t.Fatalf("%s: unexpected error %v", path, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"a..b":           "json path a..b has an empty component",
+		".a":             "json path .a has an empty component",
+		"a.":             "json path a. has an empty component",
+		"a|":             "json path a| has an empty component",
+		`a\`:             `json path a\ ends with an escape`,
+		"items.#(id==1":  "json path items.#(id==1 is missing a closing )",
+		`items.#(n=="x)`: `json path items.#(n=="x) has an unterminated string`,
+		"[a,b":           "json path [a,b is missing a closing ]",
+		"{a,b)":          "json path {a,b) has an unbalanced )",
+	}
+	for path, want := range invalid {
+		_, err := NewEqualOperator(path, 1)
+		if err == nil || err.Error() != want {
+			t.Fatalf("%s: unexpected error %v", path, err)
+		}
+	}
+
+	if _, err := NewReferenceOperator(Equal, "a", "b..c"); err == nil || err.Error() != "reference json path b..c has an empty component" {
+		t.Fatalf("unexpected reference error %v", err)
+	}
+	if _, err := NewExistsOperator("a.#(b", true); err == nil {
+		t.Fatalf("expected exists to reject the path")
+	}
+}
+
+func TestToIntBounds(t *testing.T) {
+	cases := []struct {
+		value interface{}
+		want  int
+		ok    bool
+	}{
+		{float64(1 << 62), 1 << 62, true},
+		{float64(math.MinInt), math.MinInt, true},
+		{float64(1 << 63), 0, false},
+		{json.Number("9223372036854775808"), 0, false},
+		{json.Number("9.3e18"), 0, false},
+		{2.5, 0, false},
+	}
+	for _, tc := range cases {
+		n, ok := ToInt(tc.value)
+		if n != tc.want || ok != tc.ok {
+			t.Fatalf("%v: got %d, %v", tc.value, n, ok)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package comparison
+
+import (
+	"fmt"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// OrderingOperator compares a JSON path value against a literal using lt/le/gt/ge semantics.
+// Numbers are ordered numerically and strings lexicographically.
+type OrderingOperator struct {
+	typ             Type
+	jsonPath        string
+	expected        interface{}
+	expectedResult  gjson.Result
+	pathNotFoundMsg string
+	mismatchMsg     string
+	incomparableMsg string
+}
+
+// NewOrderingOperator constructs an OrderingOperator for one of LessThan, LessEqual, GreaterThan or GreaterEqual.
+func NewOrderingOperator(t Type, jsonPath string, expected interface{}) (*OrderingOperator, error) {
+	if !isOrdering(t) {
+		return nil, fmt.Errorf("comparison operator %s is not an ordering operator", t)
+	}
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	literal, ok := literalResult(expected)
+	if !ok || (literal.Type != gjson.Number && literal.Type != gjson.String) {
+		return nil, fmt.Errorf("%s operator expects a number or string value, got %T", t, expected)
+	}
+	return &OrderingOperator{
+		typ:             t,
+		jsonPath:        jsonPath,
+		expected:        expected,
+		expectedResult:  literal,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		mismatchMsg:     fmt.Sprintf("value is not %s %v", orderingPhrase(t), expected),
+		incomparableMsg: fmt.Sprintf("value cannot be compared with %v", expected),
+	}, nil
+}
+
+// MustNewOrderingOperator panics when inputs are invalid.
+func MustNewOrderingOperator(t Type, jsonPath string, expected interface{}) *OrderingOperator {
+	op, err := NewOrderingOperator(t, jsonPath, expected)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *OrderingOperator) Name() string {
+	return string(o.typ)
+}
+
+// Spec describes the operator.
+func (o *OrderingOperator) Spec() Spec {
+	return Spec{Type: o.typ, Field: o.jsonPath, Value: o.expected}
+}
+
+// Evaluate fetches the JSON value and orders it against the expected literal.
+func (o *OrderingOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *OrderingOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *OrderingOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	cmp, ok := compareResults(actual, o.expectedResult)
+	if !ok {
+		return jsonfilter.ErrorResult(o.Name(), o.incomparableMsg)
+	}
+	if satisfiesOrdering(o.typ, cmp) {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *OrderingOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if !isOrdering(o.typ) {
+		return jsonfilter.ErrorValidationResult(o.Name(), "comparison operator is not an ordering operator")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
@@ -0,0 +1,138 @@
+package comparison
+
+import (
+	"fmt"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// ReferenceOperator compares the values of two JSON paths resolved from the same payload. It
+// supports eq/ne and the lt/le/gt/ge ordering operators.
+type ReferenceOperator struct {
+	typ             Type
+	jsonPath        string
+	refPath         string
+	pathNotFoundMsg string
+	refNotFoundMsg  string
+}
+
+// NewReferenceOperator constructs a ReferenceOperator comparing jsonPath against refPath.
+func NewReferenceOperator(t Type, jsonPath, refPath string) (*ReferenceOperator, error) {
+	if t != Equal && t != NotEqual && !isOrdering(t) {
+		return nil, fmt.Errorf("comparison operator %s does not support valueFrom", t)
+	}
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if refPath == "" {
+		return nil, fmt.Errorf("reference json path must not be empty")
+	}
+	if err := jsonfilter.CheckPath(refPath); err != nil {
+		return nil, fmt.Errorf("reference %w", err)
+	}
+	return &ReferenceOperator{
+		typ:             t,
+		jsonPath:        jsonPath,
+		refPath:         refPath,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		refNotFoundMsg:  "reference json path " + refPath + " not found",
+	}, nil
+}
+
+// MustNewReferenceOperator panics when inputs are invalid.
+func MustNewReferenceOperator(t Type, jsonPath, refPath string) *ReferenceOperator {
+	op, err := NewReferenceOperator(t, jsonPath, refPath)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *ReferenceOperator) Name() string {
+	return string(o.typ)
+}
+
+// Spec describes the operator.
+func (o *ReferenceOperator) Spec() Spec {
+	return Spec{Type: o.typ, Field: o.jsonPath, ValueFrom: o.refPath}
+}
+
+// Evaluate resolves both paths and compares the values. Mismatches report both actual values.
+func (o *ReferenceOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath), getJSONResult(json, o.refPath))
+}
+
+// EvaluateContext resolves both paths against the documents of ctx, so the reference may point into
+// another document, e.g. `@env.maxAmount`.
+func (o *ReferenceOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual, other := getContextResult(ctx, o.jsonPath), getContextResult(ctx, o.refPath)
+	res := o.evaluate(actual, other)
+	if res.Match || !ctx.Explaining() {
+		return res
+	}
+	switch {
+	case !actual.Exists():
+		res.Cause = jsonfilter.NewCause(jsonfilter.CausePathNotFound, o.jsonPath, nil, "")
+	case !other.Exists():
+		res.Cause = jsonfilter.NewCause(jsonfilter.CausePathNotFound, o.refPath, nil, "")
+	default:
+		code := jsonfilter.CauseMismatch
+		if _, comparable := compareResults(actual, other); isOrdering(o.typ) && !comparable {
+			code = jsonfilter.CauseTypeMismatch
+		}
+		// The expected value is the one found at the referenced path.
+		res.Cause = jsonfilter.NewCause(code, o.jsonPath, jsonfilter.DecodeRaw(other.Raw), actual.Raw)
+	}
+	return res
+}
+
+func (o *ReferenceOperator) evaluate(actual, other gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+	if !other.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.refNotFoundMsg)
+	}
+	return o.compare(actual, other)
+}
+
+func (o *ReferenceOperator) compare(actual, other gjson.Result) jsonfilter.EvaluationResult {
+	switch o.typ {
+	case Equal:
+		if equalResults(actual, other) {
+			return jsonfilter.ValidResult(o.Name())
+		}
+		return jsonfilter.ErrorResult(o.Name(), o.describe(actual, "did not equal", other))
+	case NotEqual:
+		if !equalResults(actual, other) {
+			return jsonfilter.ValidResult(o.Name())
+		}
+		return jsonfilter.ErrorResult(o.Name(), o.describe(actual, "equals", other))
+	default:
+		cmp, ok := compareResults(actual, other)
+		if !ok {
+			return jsonfilter.ErrorResult(o.Name(), o.describe(actual, "cannot be compared with", other))
+		}
+		if satisfiesOrdering(o.typ, cmp) {
+			return jsonfilter.ValidResult(o.Name())
+		}
+		return jsonfilter.ErrorResult(o.Name(), o.describe(actual, "is not "+orderingPhrase(o.typ), other))
+	}
+}
+
+func (o *ReferenceOperator) describe(actual gjson.Result, relation string, other gjson.Result) string {
+	return fmt.Sprintf("value %s at %s %s value %s at %s", actual.Raw, o.jsonPath, relation, other.Raw, o.refPath)
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *ReferenceOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if o.refPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "reference json path must not be empty")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
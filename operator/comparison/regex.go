@@ -5,6 +5,7 @@ import (
 	"regexp"
 
 	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
 )
 
 // RegexOperator evaluates the value of a JSON path against a compiled regular expression.
@@ -18,8 +19,8 @@ type RegexOperator struct {
 
 // NewRegexOperator creates a RegexOperator and compiles the provided pattern.
 func NewRegexOperator(jsonPath, pattern string) (*RegexOperator, error) {
-	if jsonPath == "" {
-		return nil, fmt.Errorf("json path must not be empty")
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
 	}
 	if pattern == "" {
 		return nil, fmt.Errorf("regex pattern must not be empty")
@@ -52,9 +53,23 @@ func (o *RegexOperator) Name() string {
 	return string(Regex)
 }
 
+// Spec describes the operator.
+func (o *RegexOperator) Spec() Spec {
+	return Spec{Type: Regex, Field: o.jsonPath, Value: o.pattern}
+}
+
 // Evaluate executes the regex match against the JSON value at jsonPath.
 func (o *RegexOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
-	actual := getJSONResult(json, o.jsonPath)
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *RegexOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *RegexOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
 	if !actual.Exists() {
 		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
 	}
@@ -0,0 +1,481 @@
+package comparison
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// maxSchemaViolations caps how many violations are reported in a single cause description.
+const maxSchemaViolations = 10
+
+// SchemaOperator validates the subdocument at a JSON path against an embedded JSON Schema. It supports
+// the draft 2020-12 keywords type, properties, required, additionalProperties, items, enum, const,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength, minItems, maxItems,
+// pattern and format. The schema is compiled once at construction; violations are reported with JSON
+// Pointer locations (in URI fragment form) relative to the path.
+type SchemaOperator struct {
+	jsonPath        string
+	schema          interface{}
+	root            *schemaNode
+	pathNotFoundMsg string
+}
+
+// NewSchemaOperator compiles schema and constructs a SchemaOperator.
+func NewSchemaOperator(jsonPath string, schema interface{}) (*SchemaOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	root, err := compileSchema(schema, "")
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaOperator{
+		jsonPath:        jsonPath,
+		schema:          schema,
+		root:            root,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+	}, nil
+}
+
+// MustNewSchemaOperator panics if construction fails.
+func MustNewSchemaOperator(jsonPath string, schema interface{}) *SchemaOperator {
+	op, err := NewSchemaOperator(jsonPath, schema)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *SchemaOperator) Name() string {
+	return string(Schema)
+}
+
+// Spec describes the operator. The schema is reported as it was supplied.
+func (o *SchemaOperator) Spec() Spec {
+	return Spec{Type: Schema, Field: o.jsonPath, Value: o.schema}
+}
+
+// Evaluate validates the value at jsonPath against the compiled schema.
+func (o *SchemaOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *SchemaOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *SchemaOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	var violations []string
+	o.root.validate(actual, nil, &violations)
+	if len(violations) == 0 {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), strings.Join(violations, "; "))
+}
+
+// Validate re-validates invariant fields.
+func (o *SchemaOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if o.root == nil {
+		return jsonfilter.ErrorValidationResult(o.Name(), "schema operator must have a compiled schema")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// schemaNode is a compiled schema. The boolean schema true compiles to a node without constraints and
+// false to a node with alwaysFalse set.
+type schemaNode struct {
+	alwaysFalse bool
+
+	types      []string
+	enum       []interface{}
+	constValue *interface{}
+
+	properties           map[string]*schemaNode
+	required             []string
+	additionalProperties *schemaNode
+	items                *schemaNode
+
+	minimum, maximum                   *decimal
+	exclusiveMinimum, exclusiveMaximum *decimal
+	minimumText, maximumText           string
+	exclusiveMinText, exclusiveMaxText string
+
+	minLength, maxLength int
+	minItems, maxItems   int
+
+	pattern     *regexp.Regexp
+	format      string
+	formatCheck FormatChecker
+}
+
+// pointer is a JSON Pointer built lazily on the stack and rendered only when a violation occurs.
+type pointer struct {
+	parent *pointer
+	token  string
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// String renders the pointer in URI fragment form, e.g. "#/items/0/name", or "#" for the root.
+func (p *pointer) String() string {
+	var tokens []string
+	for cur := p; cur != nil; cur = cur.parent {
+		tokens = append(tokens, pointerEscaper.Replace(cur.token))
+	}
+	var b strings.Builder
+	b.WriteByte('#')
+	for i := len(tokens) - 1; i >= 0; i-- {
+		b.WriteByte('/')
+		b.WriteString(tokens[i])
+	}
+	return b.String()
+}
+
+var schemaKeywords = map[string]struct{}{
+	"$schema": {}, "$id": {}, "$comment": {}, "title": {}, "description": {}, "examples": {}, "default": {},
+	"type": {}, "enum": {}, "const": {}, "properties": {}, "required": {}, "additionalProperties": {},
+	"items": {}, "minimum": {}, "maximum": {}, "exclusiveMinimum": {}, "exclusiveMaximum": {},
+	"minLength": {}, "maxLength": {}, "minItems": {}, "maxItems": {}, "pattern": {}, "format": {},
+}
+
+var schemaTypes = map[string]struct{}{
+	"string": {}, "number": {}, "integer": {}, "boolean": {}, "object": {}, "array": {}, "null": {},
+}
+
+// compileSchema compiles a decoded schema document. location identifies the schema in error messages.
+func compileSchema(raw interface{}, location string) (*schemaNode, error) {
+	node := &schemaNode{minLength: -1, maxLength: -1, minItems: -1, maxItems: -1}
+	if flag, ok := raw.(bool); ok {
+		node.alwaysFalse = !flag
+		return node, nil
+	}
+	def, ok := toStringMap(raw)
+	if !ok {
+		return nil, fmt.Errorf("schema at %q must be an object or boolean, got %T", schemaLocation(location), raw)
+	}
+
+	keys := make([]string, 0, len(def))
+	for key := range def {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := schemaKeywords[key]; !ok {
+			return nil, fmt.Errorf("schema keyword %q at %q is not supported", key, schemaLocation(location))
+		}
+		if err := node.compileKeyword(key, def[key], location); err != nil {
+			return nil, fmt.Errorf("schema keyword %q at %q: %w", key, schemaLocation(location), err)
+		}
+	}
+	return node, nil
+}
+
+func schemaLocation(location string) string {
+	if location == "" {
+		return "#"
+	}
+	return "#" + location
+}
+
+func (n *schemaNode) compileKeyword(key string, value interface{}, location string) error {
+	var err error
+	switch key {
+	case "type":
+		switch typed := value.(type) {
+		case string:
+			n.types = []string{typed}
+		case []interface{}:
+			for _, item := range typed {
+				name, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("expects type names")
+				}
+				n.types = append(n.types, name)
+			}
+		default:
+			return fmt.Errorf("expects a string or list of strings")
+		}
+		for _, name := range n.types {
+			if _, ok := schemaTypes[name]; !ok {
+				return fmt.Errorf("unknown type %q", name)
+			}
+		}
+	case "enum":
+		list, ok := value.([]interface{})
+		if !ok || len(list) == 0 {
+			return fmt.Errorf("expects a non-empty list")
+		}
+		for _, item := range list {
+			n.enum = append(n.enum, normalizeLiteral(item))
+		}
+	case "const":
+		literal := normalizeLiteral(value)
+		n.constValue = &literal
+	case "properties":
+		props, ok := toStringMap(value)
+		if !ok {
+			return fmt.Errorf("expects an object")
+		}
+		n.properties = make(map[string]*schemaNode, len(props))
+		for name, sub := range props {
+			if n.properties[name], err = compileSchema(sub, location+"/properties/"+name); err != nil {
+				return err
+			}
+		}
+	case "required":
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expects a list of property names")
+		}
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("expects a list of property names")
+			}
+			n.required = append(n.required, name)
+		}
+	case "additionalProperties":
+		if flag, ok := value.(bool); ok && flag {
+			// "true" behaves like an absent keyword
+			return nil
+		}
+		n.additionalProperties, err = compileSchema(value, location+"/additionalProperties")
+	case "items":
+		n.items, err = compileSchema(value, location+"/items")
+	case "minimum":
+		n.minimum, n.minimumText, err = schemaNumber(value)
+	case "maximum":
+		n.maximum, n.maximumText, err = schemaNumber(value)
+	case "exclusiveMinimum":
+		n.exclusiveMinimum, n.exclusiveMinText, err = schemaNumber(value)
+	case "exclusiveMaximum":
+		n.exclusiveMaximum, n.exclusiveMaxText, err = schemaNumber(value)
+	case "minLength":
+		n.minLength, err = schemaCount(value)
+	case "maxLength":
+		n.maxLength, err = schemaCount(value)
+	case "minItems":
+		n.minItems, err = schemaCount(value)
+	case "maxItems":
+		n.maxItems, err = schemaCount(value)
+	case "pattern":
+		expr, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expects a regular expression string")
+		}
+		n.pattern, err = regexp.Compile(expr)
+	case "format":
+		name, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expects a format name")
+		}
+		check, ok := lookupFormat(name)
+		if !ok {
+			return fmt.Errorf("format %q is not registered", name)
+		}
+		n.format, n.formatCheck = name, check
+	}
+	return err
+}
+
+func schemaNumber(value interface{}) (*decimal, string, error) {
+	text, ok := numberLiteral(value)
+	if _, isString := value.(string); isString || !ok {
+		return nil, "", fmt.Errorf("expects a number")
+	}
+	d, _ := parseDecimal(text)
+	return &d, text, nil
+}
+
+func schemaCount(value interface{}) (int, error) {
+	n, ok := ToInt(value)
+	if _, isString := value.(string); isString || !ok || n < 0 {
+		return 0, fmt.Errorf("expects a non-negative integer")
+	}
+	return n, nil
+}
+
+// validate appends violations of value against the node to out, stopping once maxSchemaViolations
+// have been collected.
+func (n *schemaNode) validate(value gjson.Result, at *pointer, out *[]string) {
+	if n == nil || len(*out) >= maxSchemaViolations {
+		return
+	}
+	report := func(format string, args ...interface{}) {
+		if len(*out) < maxSchemaViolations {
+			*out = append(*out, at.String()+": "+fmt.Sprintf(format, args...))
+		}
+	}
+	if n.alwaysFalse {
+		report("no value is allowed")
+		return
+	}
+
+	if len(n.types) > 0 && !n.matchesType(value) {
+		report("expected type %s, got %s", strings.Join(n.types, " or "), schemaTypeOf(value))
+		return
+	}
+	if n.constValue != nil && !literalEquals(value, *n.constValue) {
+		report("value %s does not equal const %v", value.Raw, *n.constValue)
+	}
+	if n.enum != nil {
+		found := false
+		for _, candidate := range n.enum {
+			if literalEquals(value, candidate) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report("value %s is not one of %v", value.Raw, n.enum)
+		}
+	}
+
+	switch {
+	case value.Type == gjson.Number:
+		n.validateNumber(value, report)
+	case value.Type == gjson.String:
+		n.validateString(value.Str, report)
+	case value.IsArray():
+		n.validateArray(value, at, out, report)
+	case value.IsObject():
+		n.validateObject(value, at, out, report)
+	}
+}
+
+func (n *schemaNode) matchesType(value gjson.Result) bool {
+	actual := schemaTypeOf(value)
+	for _, t := range n.types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaTypeOf returns the JSON Schema type name of value; integral numbers report "integer".
+func schemaTypeOf(value gjson.Result) string {
+	switch value.Type {
+	case gjson.Null:
+		return "null"
+	case gjson.True, gjson.False:
+		return "boolean"
+	case gjson.String:
+		return "string"
+	case gjson.Number:
+		if d, ok := parseDecimal(value.Raw); ok && d.point >= d.end-d.start {
+			return "integer"
+		}
+		return "number"
+	default:
+		if value.IsArray() {
+			return "array"
+		}
+		return "object"
+	}
+}
+
+func literalEquals(value gjson.Result, literal interface{}) bool {
+	if expected, ok := literalResult(literal); ok {
+		return equalResults(value, expected)
+	}
+	return reflect.DeepEqual(value.Value(), literal)
+}
+
+func (n *schemaNode) validateNumber(value gjson.Result, report func(string, ...interface{})) {
+	d, ok := parseDecimal(value.Raw)
+	if !ok {
+		return
+	}
+	if n.minimum != nil && compareDecimal(d, *n.minimum) < 0 {
+		report("value %s is less than minimum %s", value.Raw, n.minimumText)
+	}
+	if n.maximum != nil && compareDecimal(d, *n.maximum) > 0 {
+		report("value %s is greater than maximum %s", value.Raw, n.maximumText)
+	}
+	if n.exclusiveMinimum != nil && compareDecimal(d, *n.exclusiveMinimum) <= 0 {
+		report("value %s is not greater than exclusiveMinimum %s", value.Raw, n.exclusiveMinText)
+	}
+	if n.exclusiveMaximum != nil && compareDecimal(d, *n.exclusiveMaximum) >= 0 {
+		report("value %s is not less than exclusiveMaximum %s", value.Raw, n.exclusiveMaxText)
+	}
+}
+
+func (n *schemaNode) validateString(value string, report func(string, ...interface{})) {
+	if n.minLength >= 0 || n.maxLength >= 0 {
+		length := utf8.RuneCountInString(value)
+		if n.minLength >= 0 && length < n.minLength {
+			report("length %d is less than minLength %d", length, n.minLength)
+		}
+		if n.maxLength >= 0 && length > n.maxLength {
+			report("length %d is greater than maxLength %d", length, n.maxLength)
+		}
+	}
+	if n.pattern != nil && !n.pattern.MatchString(value) {
+		report("value does not match pattern %s", n.pattern)
+	}
+	if n.formatCheck != nil && !n.formatCheck(value) {
+		report("value is not a valid %s", n.format)
+	}
+}
+
+func (n *schemaNode) validateArray(value gjson.Result, at *pointer, out *[]string, report func(string, ...interface{})) {
+	count := 0
+	value.ForEach(func(_, item gjson.Result) bool {
+		if n.items != nil {
+			n.items.validate(item, &pointer{parent: at, token: strconv.Itoa(count)}, out)
+		}
+		count++
+		return true
+	})
+	if n.minItems >= 0 && count < n.minItems {
+		report("array has %d items, fewer than minItems %d", count, n.minItems)
+	}
+	if n.maxItems >= 0 && count > n.maxItems {
+		report("array has %d items, more than maxItems %d", count, n.maxItems)
+	}
+}
+
+func (n *schemaNode) validateObject(value gjson.Result, at *pointer, out *[]string, report func(string, ...interface{})) {
+	for _, name := range n.required {
+		if !value.Get(gjson.Escape(name)).Exists() {
+			report("missing required property %q", name)
+		}
+	}
+	if n.properties == nil && n.additionalProperties == nil {
+		return
+	}
+	value.ForEach(func(key, item gjson.Result) bool {
+		child := &pointer{parent: at, token: key.Str}
+		if sub, ok := n.properties[key.Str]; ok {
+			sub.validate(item, child, out)
+			return true
+		}
+		if n.additionalProperties != nil {
+			if n.additionalProperties.alwaysFalse {
+				report("additional property %q is not allowed", key.Str)
+				return true
+			}
+			n.additionalProperties.validate(item, child, out)
+		}
+		return true
+	})
+}
@@ -0,0 +1,83 @@
+package comparison
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaOperator(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"id", "items"},
+		"properties": map[string]interface{}{
+			"id":     map[string]interface{}{"type": "string", "pattern": "^[A-Z]{3}-[0-9]+$"},
+			"status": map[string]interface{}{"enum": []interface{}{"open", "closed"}},
+			"kind":   map[string]interface{}{"const": "order"},
+			"amount": map[string]interface{}{"type": "number", "minimum": 0, "exclusiveMaximum": 1000},
+			"items": map[string]interface{}{
+				"type":     "array",
+				"minItems": 1,
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"required":             []interface{}{"sku"},
+					"additionalProperties": false,
+					"properties": map[string]interface{}{
+						"sku": map[string]interface{}{"type": "string", "minLength": 3, "maxLength": 8},
+						"qty": map[string]interface{}{"type": "integer", "minimum": 1},
+					},
+				},
+			},
+		},
+	}
+	op := MustNewSchemaOperator("order", schema)
+
+	valid := `{"order":{"id":"ABC-1","status":"open","kind":"order","amount":999.99,"items":[{"sku":"abc","qty":2}]}}`
+	if res := op.Evaluate([]byte(valid)); !res.Match {
+		t.Fatalf("expected valid document: %#v", res)
+	}
+
+	invalid := `{"order":{"id":"abc","status":"pending","kind":"refund","amount":1000,"items":[{"sku":"ab","qty":1.5,"x":1},{"qty":0}]}}`
+	res := op.Evaluate([]byte(invalid))
+	if res.Match {
+		t.Fatalf("expected schema violations: %#v", res)
+	}
+	for _, want := range []string{
+		"#/id: value does not match pattern ^[A-Z]{3}-[0-9]+$",
+		`#/status: value "pending" is not one of [open closed]`,
+		`#/kind: value "refund" does not equal const order`,
+		"#/amount: value 1000 is not less than exclusiveMaximum 1000",
+		"#/items/0/sku: length 2 is less than minLength 3",
+		"#/items/0/qty: expected type integer, got number",
+		`#/items/0: additional property "x" is not allowed`,
+		`#/items/1: missing required property "sku"`,
+		"#/items/1/qty: value 0 is less than minimum 1",
+	} {
+		if !strings.Contains(res.CauseDescription, want) {
+			t.Fatalf("expected cause to contain %q, got %q", want, res.CauseDescription)
+		}
+	}
+
+	if res := op.Evaluate([]byte(`{"order":[]}`)); res.CauseDescription != "#: expected type object, got array" {
+		t.Fatalf("unexpected root cause %q", res.CauseDescription)
+	}
+}
+
+func TestSchemaOperatorCompileErrors(t *testing.T) {
+	for _, schema := range []interface{}{
+		"string",
+		map[string]interface{}{"type": "text"},
+		map[string]interface{}{"minLength": -1},
+		map[string]interface{}{"pattern": "("},
+		map[string]interface{}{"properties": map[string]interface{}{"a": map[string]interface{}{"typo": 1}}},
+		map[string]interface{}{"oneOf": []interface{}{}},
+	} {
+		if _, err := Instantiate(Schema, "v", schema); err == nil {
+			t.Fatalf("expected schema %v to be rejected", schema)
+		}
+	}
+
+	op := MustNewSchemaOperator("v", false)
+	if res := op.Evaluate([]byte(`{"v":1}`)); res.Match {
+		t.Fatalf("expected false schema to reject everything: %#v", res)
+	}
+}
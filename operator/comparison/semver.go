@@ -0,0 +1,508 @@
+package comparison
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// SemverOperator checks that the semantic version at a JSON path satisfies a range expression. Ranges
+// follow the npm syntax: comparators (`<`, `<=`, `>`, `>=`, `=`) joined by spaces, `||` alternatives,
+// caret (`^2.3`), tilde (`~2.3.1`), X-ranges (`2.x`, `2.3`) and hyphen ranges (`1.2 - 2.3`).
+// Versions are ordered by SemVer 2.0 precedence, including prerelease identifiers.
+type SemverOperator struct {
+	jsonPath        string
+	expression      string
+	ranges          [][]semverComparator
+	pathNotFoundMsg string
+	invalidMsg      string
+	mismatchMsg     string
+}
+
+// NewSemverOperator compiles the range expression and constructs a SemverOperator.
+func NewSemverOperator(jsonPath, expression string) (*SemverOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	ranges, err := parseSemverRange(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &SemverOperator{
+		jsonPath:        jsonPath,
+		expression:      expression,
+		ranges:          ranges,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		invalidMsg:      "value at json path " + jsonPath + " is not a valid semantic version",
+		mismatchMsg:     fmt.Sprintf("version does not satisfy range %s", expression),
+	}, nil
+}
+
+// MustNewSemverOperator panics if construction fails.
+func MustNewSemverOperator(jsonPath, expression string) *SemverOperator {
+	op, err := NewSemverOperator(jsonPath, expression)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *SemverOperator) Name() string {
+	return string(Semver)
+}
+
+// Spec describes the operator.
+func (o *SemverOperator) Spec() Spec {
+	return Spec{Type: Semver, Field: o.jsonPath, Value: o.expression}
+}
+
+// Evaluate parses the version at jsonPath and checks it against the compiled range.
+func (o *SemverOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *SemverOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *SemverOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	version, ok := parseSemver(strings.TrimPrefix(actual.Str, "v"))
+	if !ok {
+		return jsonfilter.ErrorResult(o.Name(), o.invalidMsg)
+	}
+
+	for _, set := range o.ranges {
+		if satisfiesAll(version, set) {
+			return jsonfilter.ValidResult(o.Name())
+		}
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate re-validates invariant fields.
+func (o *SemverOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if len(o.ranges) == 0 {
+		return jsonfilter.ErrorValidationResult(o.Name(), "semver operator must have a compiled range")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// semver is a parsed SemVer 2.0 version. Build metadata does not affect precedence and is dropped.
+type semver struct {
+	major, minor, patch uint64
+	prerelease          string
+}
+
+type semverComparator struct {
+	op      string
+	version semver
+}
+
+func satisfiesAll(v semver, set []semverComparator) bool {
+	for _, c := range set {
+		cmp := compareSemver(v, c.version)
+		var ok bool
+		switch c.op {
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		default:
+			ok = cmp == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSemver parses a complete MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD] version without allocating.
+func parseSemver(s string) (semver, bool) {
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		if !validIdentifiers(s[i+1:], false) {
+			return semver{}, false
+		}
+		s = s[:i]
+	}
+	var v semver
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		if !validIdentifiers(s[i+1:], true) {
+			return semver{}, false
+		}
+		v.prerelease = s[i+1:]
+		s = s[:i]
+	}
+
+	var ok bool
+	var part string
+	part, s, _ = strings.Cut(s, ".")
+	if v.major, ok = parseNumericIdentifier(part); !ok {
+		return semver{}, false
+	}
+	part, s, _ = strings.Cut(s, ".")
+	if v.minor, ok = parseNumericIdentifier(part); !ok {
+		return semver{}, false
+	}
+	if v.patch, ok = parseNumericIdentifier(s); !ok {
+		return semver{}, false
+	}
+	return v, true
+}
+
+func parseNumericIdentifier(s string) (uint64, bool) {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	return n, err == nil
+}
+
+// validIdentifiers checks dot-separated prerelease or build identifiers. Prerelease identifiers
+// additionally reject numeric identifiers with leading zeros.
+func validIdentifiers(s string, prerelease bool) bool {
+	if s == "" {
+		return false
+	}
+	for s != "" {
+		var ident string
+		ident, s, _ = strings.Cut(s, ".")
+		if ident == "" {
+			return false
+		}
+		numeric := true
+		for i := 0; i < len(ident); i++ {
+			c := ident[i]
+			switch {
+			case c >= '0' && c <= '9':
+			case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '-':
+				numeric = false
+			default:
+				return false
+			}
+		}
+		if prerelease && numeric && len(ident) > 1 && ident[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareSemver orders versions by SemVer 2.0 precedence.
+func compareSemver(a, b semver) int {
+	if c := compareUint(a.major, b.major); c != 0 {
+		return c
+	}
+	if c := compareUint(a.minor, b.minor); c != 0 {
+		return c
+	}
+	if c := compareUint(a.patch, b.patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(a.prerelease, b.prerelease)
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePrerelease compares prerelease strings identifier by identifier. A version without
+// prerelease has higher precedence than one with it.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	for a != "" && b != "" {
+		var ai, bi string
+		ai, a, _ = strings.Cut(a, ".")
+		bi, b, _ = strings.Cut(b, ".")
+		an, aNumeric := parseUintIdentifier(ai)
+		bn, bNumeric := parseUintIdentifier(bi)
+		switch {
+		case aNumeric && bNumeric:
+			if c := compareUint(an, bn); c != 0 {
+				return c
+			}
+		case aNumeric:
+			return -1
+		case bNumeric:
+			return 1
+		default:
+			if c := strings.Compare(ai, bi); c != 0 {
+				return c
+			}
+		}
+	}
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+func parseUintIdentifier(s string) (uint64, bool) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	return n, err == nil
+}
+
+// partialVersion is a version in a range expression where trailing components may be wildcards.
+type partialVersion struct {
+	major, minor, patch uint64
+	// parts counts the non-wildcard components (0-3).
+	parts      int
+	prerelease string
+}
+
+func (p partialVersion) floor() semver {
+	return semver{major: p.major, minor: p.minor, patch: p.patch, prerelease: p.prerelease}
+}
+
+// parseSemverRange compiles an npm-style range expression into alternative comparator sets.
+func parseSemverRange(expression string) ([][]semverComparator, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, fmt.Errorf("semver range must not be empty")
+	}
+	var ranges [][]semverComparator
+	for _, alternative := range strings.Split(expression, "||") {
+		set, err := parseComparatorSet(strings.Fields(alternative))
+		if err != nil {
+			return nil, fmt.Errorf("invalid semver range %q: %w", expression, err)
+		}
+		ranges = append(ranges, set)
+	}
+	return ranges, nil
+}
+
+func parseComparatorSet(tokens []string) ([]semverComparator, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty alternative")
+	}
+	if len(tokens) == 3 && tokens[1] == "-" {
+		return parseHyphenRange(tokens[0], tokens[2])
+	}
+
+	var set []semverComparator
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		// allow a space between the operator and the version, e.g. ">= 1.2"
+		if isComparatorOperator(token) {
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("operator %s is missing a version", token)
+			}
+			i++
+			token += tokens[i]
+		}
+		comparators, err := parseComparator(token)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, comparators...)
+	}
+	return set, nil
+}
+
+func isComparatorOperator(token string) bool {
+	switch token {
+	case "<", "<=", ">", ">=", "=", "^", "~", "~>":
+		return true
+	default:
+		return false
+	}
+}
+
+func parseComparator(token string) ([]semverComparator, error) {
+	op := ""
+	for _, prefix := range []string{"<=", ">=", "<", ">", "=", "^", "~>", "~"} {
+		if strings.HasPrefix(token, prefix) {
+			op = prefix
+			break
+		}
+	}
+	p, err := parsePartial(token[len(op):])
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "^":
+		return caretRange(p), nil
+	case "~", "~>":
+		return tildeRange(p), nil
+	case "", "=":
+		return xRange(p), nil
+	case ">=":
+		return []semverComparator{{">=", p.floor()}}, nil
+	case "<":
+		floor := p.floor()
+		if p.parts < 3 {
+			// <1.2 excludes the prereleases of 1.2.0 as well, like npm's <1.2.0-0.
+			floor.prerelease = "0"
+		}
+		return []semverComparator{{"<", floor}}, nil
+	case ">":
+		if p.parts == 3 {
+			return []semverComparator{{">", p.floor()}}, nil
+		}
+		if p.parts == 0 {
+			return []semverComparator{{"<", semver{prerelease: "0"}}}, nil
+		}
+		return []semverComparator{{">=", p.bump()}}, nil
+	default: // "<="
+		if p.parts == 3 {
+			return []semverComparator{{"<=", p.floor()}}, nil
+		}
+		if p.parts == 0 {
+			return nil, nil
+		}
+		return []semverComparator{{"<", p.bump()}}, nil
+	}
+}
+
+// bump returns the lowest version above every version matched by the partial, e.g. 1.2 -> 1.3.0-0.
+func (p partialVersion) bump() semver {
+	switch p.parts {
+	case 1:
+		return semver{major: p.major + 1, prerelease: "0"}
+	case 2:
+		return semver{major: p.major, minor: p.minor + 1, prerelease: "0"}
+	default:
+		return semver{major: p.major, minor: p.minor, patch: p.patch + 1, prerelease: "0"}
+	}
+}
+
+func xRange(p partialVersion) []semverComparator {
+	switch p.parts {
+	case 0:
+		return nil
+	case 3:
+		return []semverComparator{{"=", p.floor()}}
+	default:
+		return []semverComparator{{">=", p.floor()}, {"<", p.bump()}}
+	}
+}
+
+func tildeRange(p partialVersion) []semverComparator {
+	switch p.parts {
+	case 0:
+		return nil
+	case 1:
+		return []semverComparator{{">=", p.floor()}, {"<", p.bump()}}
+	default:
+		upper := semver{major: p.major, minor: p.minor + 1, prerelease: "0"}
+		return []semverComparator{{">=", p.floor()}, {"<", upper}}
+	}
+}
+
+// caretRange allows changes that do not modify the left-most non-zero component.
+func caretRange(p partialVersion) []semverComparator {
+	var upper semver
+	switch {
+	case p.parts == 0:
+		return nil
+	case p.major > 0 || p.parts == 1:
+		upper = semver{major: p.major + 1, prerelease: "0"}
+	case p.minor > 0 || p.parts == 2:
+		upper = semver{minor: p.minor + 1, prerelease: "0"}
+	default:
+		upper = semver{patch: p.patch + 1, prerelease: "0"}
+	}
+	return []semverComparator{{">=", p.floor()}, {"<", upper}}
+}
+
+func parseHyphenRange(from, to string) ([]semverComparator, error) {
+	lower, err := parsePartial(from)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := parsePartial(to)
+	if err != nil {
+		return nil, err
+	}
+	set := []semverComparator{{">=", lower.floor()}}
+	switch upper.parts {
+	case 0:
+	case 3:
+		set = append(set, semverComparator{"<=", upper.floor()})
+	default:
+		set = append(set, semverComparator{"<", upper.bump()})
+	}
+	return set, nil
+}
+
+// parsePartial parses versions such as 1, 1.2, 1.2.x, *, v1.2.3-beta.1.
+func parsePartial(s string) (partialVersion, error) {
+	raw := s
+	s = strings.TrimPrefix(s, "v")
+	if s == "" {
+		return partialVersion{}, fmt.Errorf("missing version")
+	}
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	var p partialVersion
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		if !validIdentifiers(s[i+1:], true) {
+			return partialVersion{}, fmt.Errorf("invalid prerelease in version %q", raw)
+		}
+		p.prerelease = s[i+1:]
+		s = s[:i]
+	}
+
+	components := strings.Split(s, ".")
+	if len(components) > 3 {
+		return partialVersion{}, fmt.Errorf("invalid version %q", raw)
+	}
+	values := [3]*uint64{&p.major, &p.minor, &p.patch}
+	wildcard := false
+	for i, component := range components {
+		if component == "x" || component == "X" || component == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			return partialVersion{}, fmt.Errorf("invalid version %q: numeric component after wildcard", raw)
+		}
+		n, ok := parseNumericIdentifier(component)
+		if !ok {
+			return partialVersion{}, fmt.Errorf("invalid version %q", raw)
+		}
+		*values[i] = n
+		p.parts++
+	}
+	if p.prerelease != "" && p.parts != 3 {
+		return partialVersion{}, fmt.Errorf("invalid version %q: prerelease requires a complete version", raw)
+	}
+	return p, nil
+}
@@ -0,0 +1,67 @@
+package comparison
+
+import "testing"
+
+func TestSemverOperatorRanges(t *testing.T) {
+	cases := []struct {
+		expression string
+		version    string
+		match      bool
+	}{
+		{">=2.3.0 <3.0.0", "2.10.0", true},
+		{">=2.3.0 <3.0.0", "2.2.9", false},
+		{">=2.3.0 <3.0.0", "3.0.0", false},
+		{"^2.3", "2.9.1", true},
+		{"^2.3", "3.0.0-alpha", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~2.3.1", "2.3.9", true},
+		{"~2.3.1", "2.4.0", false},
+		{"~2.3.1", "2.3.0", false},
+		{">=1.2 <2", "1.9.9", true},
+		{">=1.2 <2", "2.0.0", false},
+		{">= 1.2", "1.2.0", true},
+		{"1.x || >=3.1.0", "2.0.0", false},
+		{"1.x || >=3.1.0", "1.4.2", true},
+		{"1.x || >=3.1.0", "v3.1.0", true},
+		{"1.2 - 2.3", "2.3.7", true},
+		{"1.2 - 2.3", "2.4.0", false},
+		{"*", "0.0.1", true},
+		{"<=1.2", "1.2.99", true},
+		{">1.2", "1.2.99", false},
+		{"2.3.0", "2.3.0+build.5", true},
+		{">=1.0.0-alpha.1", "1.0.0-alpha.beta", true},
+		{"<1.0.0-rc.1", "1.0.0-beta.11", true},
+		{"<1.0.0-beta.2", "1.0.0-beta.11", false},
+		{"<1.0.0", "1.0.0-rc.1", true},
+		{"<2", "2.0.0-rc.1", false},
+		{"<2", "1.9.9", true},
+		{"<1.3", "1.3.0-0", false},
+		{"~> 1.2", "1.2.5", true},
+		{"~> 1.2", "1.3.0", false},
+	}
+	for _, tc := range cases {
+		op := MustNewSemverOperator("client.version", tc.expression)
+		payload := []byte(`{"client":{"version":"` + tc.version + `"}}`)
+		if res := op.Evaluate(payload); res.Match != tc.match {
+			t.Fatalf("%s against %q: unexpected result %#v", tc.version, tc.expression, res)
+		}
+	}
+}
+
+func TestSemverOperatorInvalidInput(t *testing.T) {
+	op := MustNewSemverOperator("v", "^1.0.0")
+	for _, version := range []string{"1.0", "01.0.0", "1.0.0-", "1.0.0-01", "1.0.0+", "abc"} {
+		res := op.Evaluate([]byte(`{"v":"` + version + `"}`))
+		if res.Match || res.CauseDescription != "value at json path v is not a valid semantic version" {
+			t.Fatalf("expected %q to be rejected: %#v", version, res)
+		}
+	}
+
+	for _, expression := range []string{"", "^1.x.2", ">=", "1.2.3.4", "1.2-beta"} {
+		if _, err := NewSemverOperator("v", expression); err == nil {
+			t.Fatalf("expected range %q to be rejected", expression)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package comparison
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// Unbounded marks an open SizeBounds limit.
+const Unbounded = -1
+
+// SizeBounds describes the accepted size range of a SizeOperator. Min and Max are inclusive;
+// use Unbounded to leave either side open.
+type SizeBounds struct {
+	Min int
+	Max int
+}
+
+// ExactSize returns bounds that accept exactly n.
+func ExactSize(n int) SizeBounds {
+	return SizeBounds{Min: n, Max: n}
+}
+
+// SizeOperator checks the array length, object key count or string rune length at a JSON path.
+type SizeOperator struct {
+	jsonPath        string
+	bounds          SizeBounds
+	pathNotFoundMsg string
+	noSizeMsg       string
+}
+
+// NewSizeOperator constructs a SizeOperator enforcing the provided bounds.
+func NewSizeOperator(jsonPath string, bounds SizeBounds) (*SizeOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if err := bounds.validate(); err != nil {
+		return nil, err
+	}
+	return &SizeOperator{
+		jsonPath:        jsonPath,
+		bounds:          bounds,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		noSizeMsg:       "value at json path " + jsonPath + " is not an array, object or string",
+	}, nil
+}
+
+// MustNewSizeOperator panics when inputs are invalid.
+func MustNewSizeOperator(jsonPath string, bounds SizeBounds) *SizeOperator {
+	op, err := NewSizeOperator(jsonPath, bounds)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *SizeOperator) Name() string {
+	return string(Size)
+}
+
+// Spec describes the operator.
+func (o *SizeOperator) Spec() Spec {
+	if o.bounds.Min == o.bounds.Max && o.bounds.Min != Unbounded {
+		return Spec{Type: Size, Field: o.jsonPath, Value: o.bounds.Min}
+	}
+	cfg := map[string]interface{}{}
+	if o.bounds.Min != Unbounded {
+		cfg["min"] = o.bounds.Min
+	}
+	if o.bounds.Max != Unbounded {
+		cfg["max"] = o.bounds.Max
+	}
+	return Spec{Type: Size, Field: o.jsonPath, Value: cfg}
+}
+
+// Evaluate measures the JSON value and checks it against the configured bounds.
+func (o *SizeOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *SizeOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *SizeOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	size, ok := measure(actual)
+	if !ok {
+		return jsonfilter.ErrorResult(o.Name(), o.noSizeMsg)
+	}
+
+	if cause := o.bounds.violation(size); cause != "" {
+		return jsonfilter.ErrorResult(o.Name(), cause)
+	}
+	return jsonfilter.ValidResult(o.Name())
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *SizeOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if err := o.bounds.validate(); err != nil {
+		return jsonfilter.ErrorValidationResult(o.Name(), err.Error())
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// measure returns the element count of arrays, the key count of objects and the rune count of strings.
+func measure(actual gjson.Result) (int, bool) {
+	switch {
+	case actual.Type == gjson.String:
+		return utf8.RuneCountInString(actual.Str), true
+	case actual.IsArray(), actual.IsObject():
+		size := 0
+		actual.ForEach(func(_, _ gjson.Result) bool {
+			size++
+			return true
+		})
+		return size, true
+	default:
+		return 0, false
+	}
+}
+
+func (b SizeBounds) validate() error {
+	if b.Min < Unbounded || b.Max < Unbounded {
+		return fmt.Errorf("size bounds must not be negative")
+	}
+	if b.Min == Unbounded && b.Max == Unbounded {
+		return fmt.Errorf("size operator requires at least one of eq, min or max")
+	}
+	if b.Max != Unbounded && b.Min > b.Max {
+		return fmt.Errorf("size minimum %d exceeds maximum %d", b.Min, b.Max)
+	}
+	return nil
+}
+
+// violation describes why size falls outside the bounds, or returns an empty string when it fits.
+func (b SizeBounds) violation(size int) string {
+	switch {
+	case b.Min == b.Max && size != b.Min:
+		return "size " + strconv.Itoa(size) + " does not equal expected " + strconv.Itoa(b.Min)
+	case b.Min != Unbounded && size < b.Min:
+		return "size " + strconv.Itoa(size) + " is below minimum " + strconv.Itoa(b.Min)
+	case b.Max != Unbounded && size > b.Max:
+		return "size " + strconv.Itoa(size) + " exceeds maximum " + strconv.Itoa(b.Max)
+	default:
+		return ""
+	}
+}
+
+// parseSizeBounds accepts either a plain integer (exact size) or an object with eq/min/max keys.
+func parseSizeBounds(value interface{}) (SizeBounds, error) {
+	if n, ok := ToInt(value); ok {
+		if n < 0 {
+			return SizeBounds{}, fmt.Errorf("size must not be negative, got %d", n)
+		}
+		return ExactSize(n), nil
+	}
+
+	cfg, ok := toStringMap(value)
+	if !ok {
+		return SizeBounds{}, fmt.Errorf("size operator expects an integer or an object with eq/min/max, got %T", value)
+	}
+
+	bounds := SizeBounds{Min: Unbounded, Max: Unbounded}
+	for key, raw := range cfg {
+		n, ok := ToInt(raw)
+		if !ok || n < 0 {
+			return SizeBounds{}, fmt.Errorf("size bound %s expects a non-negative integer, got %v", key, raw)
+		}
+		switch key {
+		case "eq":
+			bounds.Min, bounds.Max = n, n
+		case "min":
+			bounds.Min = n
+		case "max":
+			bounds.Max = n
+		default:
+			return SizeBounds{}, fmt.Errorf("size operator does not support bound %q", key)
+		}
+	}
+	if _, ok := cfg["eq"]; ok && len(cfg) > 1 {
+		return SizeBounds{}, fmt.Errorf("size bound eq cannot be combined with min or max")
+	}
+	return bounds, nil
+}
@@ -0,0 +1,29 @@
+package comparison
+
+// Spec describes a comparison operator in the form accepted by Instantiate, or by
+// InstantiateReference when ValueFrom is set. Rebuilding an operator from its Spec yields an
+// equivalent operator, which lets operator trees be rendered back into filter definitions.
+type Spec struct {
+	Type      Type
+	Field     string
+	Value     interface{}
+	ValueFrom string
+}
+
+// Describer is implemented by every comparison operator in this package.
+type Describer interface {
+	Spec() Spec
+}
+
+// optionMap renders an operator value that carries options next to its main value.
+func optionMap(key string, value interface{}, opts ...interface{}) interface{} {
+	cfg := map[string]interface{}{}
+	for i := 0; i+1 < len(opts); i += 2 {
+		cfg[opts[i].(string)] = opts[i+1]
+	}
+	if len(cfg) == 0 && key == "value" {
+		return value
+	}
+	cfg[key] = value
+	return cfg
+}
@@ -0,0 +1,233 @@
+package comparison
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+	"golang.org/x/text/unicode/norm"
+)
+
+// StringOptions tunes how StringOperator compares text.
+type StringOptions struct {
+	// IgnoreCase compares using simple Unicode case folding.
+	IgnoreCase bool
+	// NormalizeNFC normalizes both sides to Unicode NFC before comparing.
+	NormalizeNFC bool
+}
+
+// StringOperator implements the sw (starts with), ew (ends with) and ieq (equals ignoring case)
+// predicates without compiling a regular expression.
+type StringOperator struct {
+	typ             Type
+	jsonPath        string
+	expected        string
+	opts            StringOptions
+	pathNotFoundMsg string
+	mismatchMsg     string
+}
+
+// NewStringOperator constructs a StringOperator for StartsWith, EndsWith or EqualFold.
+// EqualFold always ignores case.
+func NewStringOperator(t Type, jsonPath, expected string, opts StringOptions) (*StringOperator, error) {
+	if !isStringPredicate(t) {
+		return nil, fmt.Errorf("comparison operator %s is not a string predicate", t)
+	}
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if t == EqualFold {
+		opts.IgnoreCase = true
+	}
+	if opts.NormalizeNFC {
+		expected = norm.NFC.String(expected)
+	}
+	op := &StringOperator{
+		typ:             t,
+		jsonPath:        jsonPath,
+		expected:        expected,
+		opts:            opts,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+	}
+	switch t {
+	case StartsWith:
+		op.mismatchMsg = fmt.Sprintf("value does not start with %s", expected)
+	case EndsWith:
+		op.mismatchMsg = fmt.Sprintf("value does not end with %s", expected)
+	default:
+		op.mismatchMsg = fmt.Sprintf("value did not equal expected %s ignoring case", expected)
+	}
+	return op, nil
+}
+
+// MustNewStringOperator panics when inputs are invalid.
+func MustNewStringOperator(t Type, jsonPath, expected string, opts StringOptions) *StringOperator {
+	op, err := NewStringOperator(t, jsonPath, expected, opts)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *StringOperator) Name() string {
+	return string(o.typ)
+}
+
+// Spec describes the operator.
+func (o *StringOperator) Spec() Spec {
+	var opts []interface{}
+	if o.opts.IgnoreCase && o.typ != EqualFold {
+		opts = append(opts, "ignoreCase", true)
+	}
+	if o.opts.NormalizeNFC {
+		opts = append(opts, "normalize", "nfc")
+	}
+	return Spec{Type: o.typ, Field: o.jsonPath, Value: optionMap("value", o.expected, opts...)}
+}
+
+// Evaluate fetches the JSON value and applies the string predicate.
+func (o *StringOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *StringOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *StringOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	value := actual.Str
+	if o.opts.NormalizeNFC && !norm.NFC.IsNormalString(value) {
+		value = norm.NFC.String(value)
+	}
+
+	if o.matches(value) {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *StringOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if !isStringPredicate(o.typ) {
+		return jsonfilter.ErrorValidationResult(o.Name(), "comparison operator is not a string predicate")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+func (o *StringOperator) matches(value string) bool {
+	switch o.typ {
+	case StartsWith:
+		if o.opts.IgnoreCase {
+			return hasPrefixFold(value, o.expected)
+		}
+		return strings.HasPrefix(value, o.expected)
+	case EndsWith:
+		if o.opts.IgnoreCase {
+			return hasSuffixFold(value, o.expected)
+		}
+		return strings.HasSuffix(value, o.expected)
+	default:
+		return strings.EqualFold(value, o.expected)
+	}
+}
+
+func isStringPredicate(t Type) bool {
+	return t == StartsWith || t == EndsWith || t == EqualFold
+}
+
+// hasPrefixFold reports whether s begins with prefix under simple Unicode case folding. Folded runes
+// may differ in encoded width, so the strings are walked rune by rune instead of sliced by length.
+func hasPrefixFold(s, prefix string) bool {
+	for prefix != "" {
+		if s == "" {
+			return false
+		}
+		pr, pn := utf8.DecodeRuneInString(prefix)
+		sr, sn := utf8.DecodeRuneInString(s)
+		if !equalFoldRune(sr, pr) {
+			return false
+		}
+		prefix, s = prefix[pn:], s[sn:]
+	}
+	return true
+}
+
+// hasSuffixFold is the suffix counterpart of hasPrefixFold.
+func hasSuffixFold(s, suffix string) bool {
+	for suffix != "" {
+		if s == "" {
+			return false
+		}
+		pr, pn := utf8.DecodeLastRuneInString(suffix)
+		sr, sn := utf8.DecodeLastRuneInString(s)
+		if !equalFoldRune(sr, pr) {
+			return false
+		}
+		suffix, s = suffix[:len(suffix)-pn], s[:len(s)-sn]
+	}
+	return true
+}
+
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStringPredicate accepts either the expected string or an object with value, ignoreCase and
+// normalize keys.
+func parseStringPredicate(t Type, value interface{}) (string, StringOptions, error) {
+	if expected, ok := value.(string); ok {
+		return expected, StringOptions{}, nil
+	}
+
+	cfg, ok := toStringMap(value)
+	if !ok {
+		return "", StringOptions{}, fmt.Errorf("%s operator expects a string or an object with value, got %T", t, value)
+	}
+
+	var opts StringOptions
+	expected, ok := cfg["value"].(string)
+	if !ok {
+		return "", StringOptions{}, fmt.Errorf("%s operator expects a string value", t)
+	}
+	for key, raw := range cfg {
+		switch key {
+		case "value":
+		case "ignoreCase":
+			flag, ok := raw.(bool)
+			if !ok {
+				return "", StringOptions{}, fmt.Errorf("%s option ignoreCase expects a boolean, got %T", t, raw)
+			}
+			opts.IgnoreCase = flag
+		case "normalize":
+			form, ok := raw.(string)
+			if !ok || !strings.EqualFold(form, "nfc") {
+				return "", StringOptions{}, fmt.Errorf("%s option normalize only supports nfc, got %v", t, raw)
+			}
+			opts.NormalizeNFC = true
+		default:
+			return "", StringOptions{}, fmt.Errorf("%s operator does not support option %q", t, key)
+		}
+	}
+	return expected, opts, nil
+}
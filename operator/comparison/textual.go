@@ -0,0 +1,165 @@
+package comparison
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// TextOperator implements eq, ne and rx the way telekom/JSON-Filter does: the value read from the
+// payload is compared through its textual form rather than its JSON type. Strings contribute their
+// content and every other value its compact JSON spelling, so 1 equals "1" and true equals "true",
+// while 1 and 1.0 differ. Regular expressions must match the whole text, as with Java's
+// String.matches.
+type TextOperator struct {
+	typ             Type
+	jsonPath        string
+	expected        interface{}
+	text            string
+	compiledRe      *regexp.Regexp
+	pathNotFoundMsg string
+	mismatchMsg     string
+}
+
+// NewTextOperator constructs a TextOperator for Equal, NotEqual or Regex.
+func NewTextOperator(t Type, jsonPath string, expected interface{}) (*TextOperator, error) {
+	if t != Equal && t != NotEqual && t != Regex {
+		return nil, fmt.Errorf("comparison operator %s has no textual form", t)
+	}
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	text, err := literalText(expected)
+	if err != nil {
+		return nil, fmt.Errorf("%s operator %w", t, err)
+	}
+	op := &TextOperator{
+		typ:             t,
+		jsonPath:        jsonPath,
+		expected:        expected,
+		text:            text,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+	}
+	switch t {
+	case Regex:
+		if text == "" {
+			return nil, fmt.Errorf("regex pattern must not be empty")
+		}
+		compiled, err := regexp.Compile(`^(?:` + text + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex pattern: %w", err)
+		}
+		op.compiledRe = compiled
+		op.mismatchMsg = fmt.Sprintf("value does not match regex %s", text)
+	case NotEqual:
+		op.mismatchMsg = fmt.Sprintf("value equals %v", expected)
+	default:
+		op.mismatchMsg = fmt.Sprintf("value did not equal expected %v", expected)
+	}
+	return op, nil
+}
+
+// MustNewTextOperator panics when inputs are invalid.
+func MustNewTextOperator(t Type, jsonPath string, expected interface{}) *TextOperator {
+	op, err := NewTextOperator(t, jsonPath, expected)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *TextOperator) Name() string {
+	return string(o.typ)
+}
+
+// Spec describes the operator.
+func (o *TextOperator) Spec() Spec {
+	return Spec{Type: o.typ, Field: o.jsonPath, Value: o.expected}
+}
+
+// Evaluate fetches the JSON value and compares its text with the expected literal.
+func (o *TextOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *TextOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *TextOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	text := resultText(actual)
+	var match bool
+	switch o.typ {
+	case Regex:
+		match = o.compiledRe.MatchString(text)
+	case NotEqual:
+		match = text != o.text
+	default:
+		match = text == o.text
+	}
+	if match {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *TextOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if o.typ == Regex && o.compiledRe == nil {
+		return jsonfilter.ErrorValidationResult(o.Name(), "regex operator must have a compiled pattern")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+// literalText spells a filter literal the way resultText spells payload values.
+func literalText(value interface{}) (string, error) {
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case bool:
+		if typed {
+			return "true", nil
+		}
+		return "false", nil
+	case nil:
+		return "null", nil
+	}
+	if raw, ok := numberLiteral(value); ok {
+		return raw, nil
+	}
+	encoded, err := json.Marshal(normalizeLiteral(value))
+	if err != nil {
+		return "", fmt.Errorf("value %v has no textual form", value)
+	}
+	return string(encoded), nil
+}
+
+// resultText returns the content of strings and the compact JSON spelling of any other value.
+func resultText(actual gjson.Result) string {
+	switch actual.Type {
+	case gjson.String:
+		return actual.Str
+	case gjson.JSON:
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, []byte(actual.Raw)); err != nil {
+			return actual.Raw
+		}
+		return compact.String()
+	default:
+		return actual.Raw
+	}
+}
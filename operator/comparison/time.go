@@ -0,0 +1,460 @@
+package comparison
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// EpochUnit selects how numeric payload timestamps are interpreted.
+type EpochUnit string
+
+const (
+	EpochSeconds      EpochUnit = "s"
+	EpochMilliseconds EpochUnit = "ms"
+)
+
+// TimeOptions tunes how TimeOperator parses payload timestamps and reads the current time.
+type TimeOptions struct {
+	// Layouts lists time.Parse layouts tried in order for string values. Defaults to RFC 3339.
+	Layouts []string
+	// Epoch interprets numeric values as Unix seconds (default) or milliseconds.
+	Epoch EpochUnit
+	// Clock returns the evaluation time for relative bounds. Defaults to time.Now.
+	Clock func() time.Time
+}
+
+// TimeBound is either an absolute instant or an offset relative to the evaluation time.
+type TimeBound struct {
+	absolute time.Time
+	offset   time.Duration
+	relative bool
+	expr     string
+}
+
+// AbsoluteTime returns a bound fixed at t.
+func AbsoluteTime(t time.Time) TimeBound {
+	return TimeBound{absolute: t, expr: t.Format(time.RFC3339Nano)}
+}
+
+// RelativeTime returns a bound offset from the evaluation time, e.g. -15*time.Minute for "now-15m".
+func RelativeTime(offset time.Duration) TimeBound {
+	expr := "now"
+	if offset > 0 {
+		expr += "+" + offset.String()
+	} else if offset < 0 {
+		expr += offset.String()
+	}
+	return TimeBound{offset: offset, relative: true, expr: expr}
+}
+
+func (b TimeBound) resolve(now time.Time) time.Time {
+	if b.relative {
+		return now.Add(b.offset)
+	}
+	return b.absolute
+}
+
+// String renders the bound as it would appear in a filter definition.
+func (b TimeBound) String() string {
+	return b.expr
+}
+
+// TimeOperator compares the timestamp at a JSON path against absolute or relative bounds. Before and
+// After use a single bound; Within requires the timestamp to fall inside [from, to], where either
+// side may be left open.
+type TimeOperator struct {
+	typ             Type
+	jsonPath        string
+	from, to        *TimeBound
+	opts            TimeOptions
+	pathNotFoundMsg string
+	invalidMsg      string
+	mismatchMsg     string
+}
+
+// NewTimeOperator constructs a Before or After operator against bound.
+func NewTimeOperator(t Type, jsonPath string, bound TimeBound, opts TimeOptions) (*TimeOperator, error) {
+	switch t {
+	case Before:
+		return newTimeOperator(t, jsonPath, nil, &bound, opts)
+	case After:
+		return newTimeOperator(t, jsonPath, &bound, nil, opts)
+	default:
+		return nil, fmt.Errorf("comparison operator %s is not a single-bound time operator", t)
+	}
+}
+
+// NewWithinOperator constructs a Within operator. Nil bounds leave that side open.
+func NewWithinOperator(jsonPath string, from, to *TimeBound, opts TimeOptions) (*TimeOperator, error) {
+	if from == nil && to == nil {
+		return nil, fmt.Errorf("within operator requires from or to")
+	}
+	return newTimeOperator(Within, jsonPath, from, to, opts)
+}
+
+func newTimeOperator(t Type, jsonPath string, from, to *TimeBound, opts TimeOptions) (*TimeOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if len(opts.Layouts) == 0 {
+		opts.Layouts = []string{time.RFC3339Nano}
+	}
+	switch opts.Epoch {
+	case "":
+		opts.Epoch = EpochSeconds
+	case EpochSeconds, EpochMilliseconds:
+	default:
+		return nil, fmt.Errorf("unsupported epoch unit %q", opts.Epoch)
+	}
+	if opts.Clock == nil {
+		opts.Clock = time.Now
+	}
+
+	op := &TimeOperator{
+		typ:             t,
+		jsonPath:        jsonPath,
+		from:            from,
+		to:              to,
+		opts:            opts,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		invalidMsg:      "value at json path " + jsonPath + " is not a recognised timestamp",
+	}
+	switch t {
+	case Before:
+		op.mismatchMsg = fmt.Sprintf("time is not before %s", to)
+	case After:
+		op.mismatchMsg = fmt.Sprintf("time is not after %s", from)
+	default:
+		op.mismatchMsg = fmt.Sprintf("time is not within %s and %s", describeBound(from), describeBound(to))
+	}
+	return op, nil
+}
+
+func describeBound(b *TimeBound) string {
+	if b == nil {
+		return "open"
+	}
+	return b.String()
+}
+
+// MustNewTimeOperator panics when inputs are invalid.
+func MustNewTimeOperator(t Type, jsonPath string, bound TimeBound, opts TimeOptions) *TimeOperator {
+	op, err := NewTimeOperator(t, jsonPath, bound, opts)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// MustNewWithinOperator panics when inputs are invalid.
+func MustNewWithinOperator(jsonPath string, from, to *TimeBound, opts TimeOptions) *TimeOperator {
+	op, err := NewWithinOperator(jsonPath, from, to, opts)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the operator identifier.
+func (o *TimeOperator) Name() string {
+	return string(o.typ)
+}
+
+// Spec describes the operator. The clock is not part of the description.
+func (o *TimeOperator) Spec() Spec {
+	cfg := map[string]interface{}{}
+	if len(o.opts.Layouts) != 1 || o.opts.Layouts[0] != time.RFC3339Nano {
+		layouts := make([]interface{}, len(o.opts.Layouts))
+		for i, layout := range o.opts.Layouts {
+			layouts[i] = layout
+		}
+		cfg["layouts"] = layouts
+	}
+	if o.opts.Epoch != EpochSeconds {
+		cfg["epoch"] = string(o.opts.Epoch)
+	}
+
+	switch o.typ {
+	case Before:
+		cfg["value"] = o.to.String()
+	case After:
+		cfg["value"] = o.from.String()
+	default:
+		if o.from != nil {
+			cfg["from"] = o.from.String()
+		}
+		if o.to != nil {
+			cfg["to"] = o.to.String()
+		}
+	}
+	if value, ok := cfg["value"]; ok && len(cfg) == 1 {
+		return Spec{Type: o.typ, Field: o.jsonPath, Value: value}
+	}
+	return Spec{Type: o.typ, Field: o.jsonPath, Value: cfg}
+}
+
+// Evaluate parses the timestamp at jsonPath and compares it with the bounds resolved against the clock.
+func (o *TimeOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath))
+}
+
+// EvaluateContext resolves the path against the documents of ctx.
+func (o *TimeOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	actual := getContextResult(ctx, o.jsonPath)
+	return explain(ctx, o, actual, o.evaluate(actual))
+}
+
+func (o *TimeOperator) evaluate(actual gjson.Result) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg)
+	}
+
+	ts, ok := o.parse(actual)
+	if !ok {
+		return jsonfilter.ErrorResult(o.Name(), o.invalidMsg)
+	}
+
+	if o.matches(ts, o.opts.Clock()) {
+		return jsonfilter.ValidResult(o.Name())
+	}
+	return jsonfilter.ErrorResult(o.Name(), o.mismatchMsg)
+}
+
+// Validate ensures the operator is correctly configured.
+func (o *TimeOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if o.from == nil && o.to == nil {
+		return jsonfilter.ErrorValidationResult(o.Name(), "time operator requires at least one bound")
+	}
+	return jsonfilter.ValidValidationResult(o.Name())
+}
+
+func (o *TimeOperator) matches(ts, now time.Time) bool {
+	switch o.typ {
+	case Before:
+		return ts.Before(o.to.resolve(now))
+	case After:
+		return ts.After(o.from.resolve(now))
+	default:
+		if o.from != nil && ts.Before(o.from.resolve(now)) {
+			return false
+		}
+		if o.to != nil && ts.After(o.to.resolve(now)) {
+			return false
+		}
+		return true
+	}
+}
+
+func (o *TimeOperator) parse(actual gjson.Result) (time.Time, bool) {
+	switch actual.Type {
+	case gjson.Number:
+		return fromEpoch(actual.Num, o.opts.Epoch)
+	case gjson.String:
+		for _, layout := range o.opts.Layouts {
+			if ts, err := time.Parse(layout, actual.Str); err == nil {
+				return ts, true
+			}
+		}
+		return time.Time{}, false
+	default:
+		return time.Time{}, false
+	}
+}
+
+func fromEpoch(value float64, unit EpochUnit) (time.Time, bool) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return time.Time{}, false
+	}
+	if unit == EpochMilliseconds {
+		return time.UnixMilli(int64(value)), true
+	}
+	sec, frac := math.Modf(value)
+	return time.Unix(int64(sec), int64(frac*1e9)), true
+}
+
+// ParseTimeBound parses "now", "now+1d", "now-15m", RFC 3339 timestamps or any of the provided layouts.
+func ParseTimeBound(expr string, layouts ...string) (TimeBound, error) {
+	trimmed := strings.TrimSpace(expr)
+	if trimmed == "now" {
+		return RelativeTime(0), nil
+	}
+	if rest, ok := strings.CutPrefix(trimmed, "now"); ok {
+		offset, err := parseRelativeDuration(strings.ReplaceAll(rest, " ", ""))
+		if err != nil {
+			return TimeBound{}, fmt.Errorf("invalid relative time %q: %w", expr, err)
+		}
+		return TimeBound{offset: offset, relative: true, expr: trimmed}, nil
+	}
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339Nano}
+	}
+	for _, layout := range layouts {
+		if ts, err := time.Parse(layout, trimmed); err == nil {
+			return TimeBound{absolute: ts, expr: trimmed}, nil
+		}
+	}
+	return TimeBound{}, fmt.Errorf("invalid time %q", expr)
+}
+
+// parseRelativeDuration extends time.ParseDuration with d (24h) and w (7d) units, e.g. "+1d12h".
+func parseRelativeDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("missing duration")
+	}
+	sign := time.Duration(1)
+	switch s[0] {
+	case '-':
+		sign = -1
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, fmt.Errorf("missing duration")
+	}
+
+	var total time.Duration
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+			i++
+		}
+		j := i
+		for j < len(s) && !(s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+			j++
+		}
+		if i == 0 || j == i {
+			return 0, fmt.Errorf("malformed duration segment %q", s)
+		}
+		number, unit := s[:i], s[i:j]
+		var part time.Duration
+		switch unit {
+		case "d", "w":
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, err
+			}
+			day := 24 * time.Hour
+			if unit == "w" {
+				day *= 7
+			}
+			part = time.Duration(n * float64(day))
+		default:
+			d, err := time.ParseDuration(number + unit)
+			if err != nil {
+				return 0, err
+			}
+			part = d
+		}
+		total += part
+		s = s[j:]
+	}
+	return sign * total, nil
+}
+
+// parseTimeConfig reads the before/after/within value. Before and after accept a bound expression or
+// an object with value; within accepts a duration such as "24h" (the last 24 hours), "+1h" (the next
+// hour) or an object with from/to. Objects may also carry layouts and epoch.
+func parseTimeConfig(t Type, value interface{}) (from, to *TimeBound, opts TimeOptions, err error) {
+	cfg, isMap := toStringMap(value)
+	if !isMap {
+		cfg = map[string]interface{}{"value": value}
+	}
+
+	for key, raw := range cfg {
+		switch key {
+		case "value":
+		case "from", "to":
+			if t != Within {
+				return nil, nil, opts, fmt.Errorf("%s operator does not support option %q", t, key)
+			}
+			if _, ok := cfg["value"]; ok {
+				return nil, nil, opts, fmt.Errorf("within operator accepts either a duration value or from/to")
+			}
+		case "layouts":
+			list, ok := raw.([]interface{})
+			if !ok {
+				return nil, nil, opts, fmt.Errorf("%s option layouts expects a list of strings", t)
+			}
+			for _, item := range list {
+				layout, ok := item.(string)
+				if !ok || layout == "" {
+					return nil, nil, opts, fmt.Errorf("%s option layouts expects a list of strings", t)
+				}
+				opts.Layouts = append(opts.Layouts, layout)
+			}
+		case "epoch":
+			unit, _ := raw.(string)
+			opts.Epoch = EpochUnit(unit)
+			if opts.Epoch != EpochSeconds && opts.Epoch != EpochMilliseconds {
+				return nil, nil, opts, fmt.Errorf("%s option epoch expects s or ms, got %v", t, raw)
+			}
+		default:
+			return nil, nil, opts, fmt.Errorf("%s operator does not support option %q", t, key)
+		}
+	}
+
+	parseBound := func(key string) (*TimeBound, error) {
+		raw, ok := cfg[key]
+		if !ok {
+			return nil, nil
+		}
+		expr, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s option %s expects a time expression, got %T", t, key, raw)
+		}
+		bound, err := ParseTimeBound(expr, opts.Layouts...)
+		if err != nil {
+			return nil, err
+		}
+		return &bound, nil
+	}
+
+	if t != Within {
+		if _, ok := cfg["value"]; !ok {
+			return nil, nil, opts, fmt.Errorf("%s operator requires a time value", t)
+		}
+		bound, err := parseBound("value")
+		if err != nil {
+			return nil, nil, opts, err
+		}
+		if t == Before {
+			return nil, bound, opts, nil
+		}
+		return bound, nil, opts, nil
+	}
+
+	if raw, ok := cfg["value"]; ok {
+		window, _ := raw.(string)
+		offset, err := parseRelativeDuration(window)
+		if err != nil {
+			return nil, nil, opts, fmt.Errorf("within operator expects a duration window: %w", err)
+		}
+		now := RelativeTime(0)
+		if strings.HasPrefix(window, "+") {
+			end := RelativeTime(offset)
+			return &now, &end, opts, nil
+		}
+		if offset > 0 {
+			offset = -offset
+		}
+		start := RelativeTime(offset)
+		return &start, &now, opts, nil
+	}
+	if from, err = parseBound("from"); err != nil {
+		return nil, nil, opts, err
+	}
+	if to, err = parseBound("to"); err != nil {
+		return nil, nil, opts, err
+	}
+	return from, to, opts, nil
+}
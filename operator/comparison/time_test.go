@@ -0,0 +1,79 @@
+package comparison
+
+import (
+	"testing"
+	"time"
+)
+
+var fixedNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func fixedClock() time.Time { return fixedNow }
+
+func TestTimeOperatorRelativeBounds(t *testing.T) {
+	cases := []struct {
+		typ     Type
+		value   interface{}
+		payload string
+		match   bool
+	}{
+		{After, "now", `{"ts":"2024-05-01T12:00:01Z"}`, true},
+		{After, "now", `{"ts":"2024-05-01T11:59:59Z"}`, false},
+		{Before, "now+1d", `{"ts":"2024-05-02T11:00:00+00:00"}`, true},
+		{Before, "now+1d", `{"ts":"2024-05-02T13:00:00Z"}`, false},
+		{Within, "24h", `{"ts":"2024-04-30T13:00:00Z"}`, true},
+		{Within, "24h", `{"ts":"2024-04-30T11:00:00Z"}`, false},
+		{Within, "15m", `{"ts":1714564800}`, true},
+		{Within, "+1h", `{"ts":"2024-05-01T12:30:00Z"}`, true},
+		{Within, "+1h", `{"ts":"2024-05-01T11:30:00Z"}`, false},
+		{Within, map[string]interface{}{"from": "2024-01-01T00:00:00Z", "to": "now-1w"}, `{"ts":"2024-04-20T00:00:00Z"}`, true},
+		{Within, map[string]interface{}{"from": "2024-01-01T00:00:00Z", "to": "now-1w"}, `{"ts":"2024-04-28T00:00:00Z"}`, false},
+		{After, map[string]interface{}{"value": "now-1h", "epoch": "ms"}, `{"ts":1714563000000}`, true},
+		{Before, map[string]interface{}{"value": "2024-05-01", "layouts": []interface{}{"2006-01-02"}}, `{"ts":"2024-04-30"}`, true},
+	}
+	for _, tc := range cases {
+		op, err := Instantiate(tc.typ, "ts", tc.value, WithClock(fixedClock))
+		if err != nil {
+			t.Fatalf("%s %v: unexpected error: %v", tc.typ, tc.value, err)
+		}
+		if res := op.Evaluate([]byte(tc.payload)); res.Match != tc.match {
+			t.Fatalf("%s %v on %s: unexpected result %#v", tc.typ, tc.value, tc.payload, res)
+		}
+	}
+}
+
+func TestTimeOperatorInvalidInput(t *testing.T) {
+	op := MustNewTimeOperator(Before, "ts", RelativeTime(0), TimeOptions{Clock: fixedClock})
+	res := op.Evaluate([]byte(`{"ts":"yesterday"}`))
+	if res.Match || res.CauseDescription != "value at json path ts is not a recognised timestamp" {
+		t.Fatalf("expected unparseable timestamp to fail: %#v", res)
+	}
+
+	for _, value := range []interface{}{"now+", "now+3x", "tomorrow", map[string]interface{}{"value": "1h", "from": "now"}} {
+		typ := Before
+		if _, ok := value.(map[string]interface{}); ok {
+			typ = Within
+		}
+		if _, err := Instantiate(typ, "ts", value); err == nil {
+			t.Fatalf("expected %v to be rejected", value)
+		}
+	}
+}
+
+func TestParseRelativeDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"+1d":    24 * time.Hour,
+		"-15m":   -15 * time.Minute,
+		"1w2d":   9 * 24 * time.Hour,
+		"1d12h":  36 * time.Hour,
+		"+1.5h":  90 * time.Minute,
+		"-1h30m": -90 * time.Minute,
+		"250ms":  250 * time.Millisecond,
+		"+0.5d":  12 * time.Hour,
+	}
+	for input, want := range cases {
+		got, err := parseRelativeDuration(input)
+		if err != nil || got != want {
+			t.Fatalf("parseRelativeDuration(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+}
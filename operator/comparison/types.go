@@ -1,6 +1,9 @@
 package comparison
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 // Type enumerates supported comparison operators.
 type Type string
@@ -17,6 +20,22 @@ const (
 	NotIn        Type = "nin"
 	Contains     Type = "ct"
 	NotContains  Type = "nct"
+	Size         Type = "size"
+	StartsWith   Type = "sw"
+	EndsWith     Type = "ew"
+	EqualFold    Type = "ieq"
+	Like         Type = "like"
+	Semver       Type = "semver"
+	Before       Type = "before"
+	After        Type = "after"
+	Within       Type = "within"
+	CIDR         Type = "cidr"
+	Between      Type = "between"
+	Approx       Type = "approx"
+	Format       Type = "format"
+	Schema       Type = "schema"
+	Exists       Type = "exists"
+	JSONType     Type = "type"
 )
 
 var allTypes = map[Type]struct{}{
@@ -31,17 +50,55 @@ var allTypes = map[Type]struct{}{
 	NotIn:        {},
 	Contains:     {},
 	NotContains:  {},
+	Size:         {},
+	StartsWith:   {},
+	EndsWith:     {},
+	EqualFold:    {},
+	Like:         {},
+	Semver:       {},
+	Before:       {},
+	After:        {},
+	Within:       {},
+	CIDR:         {},
+	Between:      {},
+	Approx:       {},
+	Format:       {},
+	Schema:       {},
+	Exists:       {},
+	JSONType:     {},
+}
+
+// typeAliases maps alternative spellings onto their canonical Type.
+var typeAliases = map[string]Type{
+	"len":  Size,
+	"glob": Like,
 }
 
 // ParseType validates and returns the corresponding Type.
 func ParseType(op string) (Type, error) {
 	t := Type(op)
+	if alias, ok := typeAliases[op]; ok {
+		t = alias
+	}
 	if _, ok := allTypes[t]; !ok {
 		return "", fmt.Errorf("comparison operator %q is not supported", op)
 	}
 	return t, nil
 }
 
+// Names returns every accepted operator name, including aliases, in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(allTypes)+len(typeAliases))
+	for t := range allTypes {
+		names = append(names, string(t))
+	}
+	for alias := range typeAliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // MustParseType panics when the provided operator string is not supported.
 func MustParseType(op string) Type {
 	t, err := ParseType(op)
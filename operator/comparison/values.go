@@ -0,0 +1,135 @@
+package comparison
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ToInt converts numeric literals decoded from YAML or JSON filter definitions into an int.
+// Fractional floats and values outside the int range are rejected; strings holding an integer are
+// accepted.
+func ToInt(value interface{}) (int, bool) {
+	switch typed := value.(type) {
+	case int:
+		return typed, true
+	case int8:
+		return int(typed), true
+	case int16:
+		return int(typed), true
+	case int32:
+		return int(typed), true
+	case int64:
+		if typed < math.MinInt || typed > math.MaxInt {
+			return 0, false
+		}
+		return int(typed), true
+	case uint:
+		if typed > math.MaxInt {
+			return 0, false
+		}
+		return int(typed), true
+	case uint8:
+		return int(typed), true
+	case uint16:
+		return int(typed), true
+	case uint32:
+		return int(typed), true
+	case uint64:
+		if typed > math.MaxInt {
+			return 0, false
+		}
+		return int(typed), true
+	case float32:
+		return floatToInt(float64(typed))
+	case float64:
+		return floatToInt(typed)
+	case json.Number:
+		if n, err := typed.Int64(); err == nil {
+			return ToInt(n)
+		}
+		f, err := typed.Float64()
+		if err != nil {
+			return 0, false
+		}
+		return floatToInt(f)
+	case string:
+		n, err := strconv.Atoi(typed)
+		if err != nil {
+			return 0, false
+		}
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// toFloat converts numeric literals into a float64.
+func toFloat(value interface{}) (float64, bool) {
+	text, ok := numberLiteral(value)
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	return f, err == nil
+}
+
+func floatToInt(f float64) (int, bool) {
+	if f != math.Trunc(f) || f < math.MinInt || f >= math.MaxInt {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// toStringMap converts decoded YAML/JSON objects into a string keyed map.
+func toStringMap(value interface{}) (map[string]interface{}, bool) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return typed, true
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			key, ok := k.(string)
+			if !ok {
+				return nil, false
+			}
+			converted[key] = v
+		}
+		return converted, true
+	default:
+		return nil, false
+	}
+}
+
+// normalizeLiteral converts json.Number values nested in composite literals into float64 so that they
+// compare equal to gjson's Value() representation of the payload.
+func normalizeLiteral(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case json.Number:
+		if f, err := typed.Float64(); err == nil {
+			return f
+		}
+		return typed.String()
+	case []interface{}:
+		out := make([]interface{}, len(typed))
+		for i, item := range typed {
+			out[i] = normalizeLiteral(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			out[k] = normalizeLiteral(v)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			out[fmt.Sprint(k)] = normalizeLiteral(v)
+		}
+		return out
+	default:
+		return value
+	}
+}
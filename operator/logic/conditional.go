@@ -0,0 +1,142 @@
+package logic
+
+import (
+	"fmt"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+)
+
+// If identifies the conditional operator built by NewConditionalOperator. It is not accepted by
+// NewOperator because it has named children rather than a child list.
+const If Type = "if"
+
+// ConditionalOperator evaluates its condition exactly once and then only the selected branch. When
+// explaining, the result's children are the condition result followed by the result of the branch
+// that ran, so the condition's Match tells which branch was taken. Without an else branch a false
+// condition matches.
+type ConditionalOperator struct {
+	condition jsonfilter.Operator
+	then      jsonfilter.Operator
+	otherwise jsonfilter.Operator
+}
+
+// NewConditionalOperator builds an if/then/else operator. otherwise may be nil.
+func NewConditionalOperator(condition, then, otherwise jsonfilter.Operator) (*ConditionalOperator, error) {
+	if condition == nil {
+		return nil, fmt.Errorf("conditional operator requires a condition")
+	}
+	if then == nil {
+		return nil, fmt.Errorf("conditional operator requires a then branch")
+	}
+	return &ConditionalOperator{condition: condition, then: then, otherwise: otherwise}, nil
+}
+
+// MustNewConditionalOperator panics when construction fails.
+func MustNewConditionalOperator(condition, then, otherwise jsonfilter.Operator) *ConditionalOperator {
+	op, err := NewConditionalOperator(condition, then, otherwise)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the identifier of the conditional operator.
+func (o *ConditionalOperator) Name() string {
+	return string(If)
+}
+
+// Condition returns the operator that selects the branch.
+func (o *ConditionalOperator) Condition() jsonfilter.Operator {
+	return o.condition
+}
+
+// Then returns the branch evaluated when the condition matches.
+func (o *ConditionalOperator) Then() jsonfilter.Operator {
+	return o.then
+}
+
+// Else returns the branch evaluated when the condition does not match, or nil.
+func (o *ConditionalOperator) Else() jsonfilter.Operator {
+	return o.otherwise
+}
+
+// Evaluate runs the condition and then the branch it selects.
+func (o *ConditionalOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(json, nil)
+}
+
+// EvaluateContext runs the condition and the selected branch against the documents of ctx.
+func (o *ConditionalOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	return o.evaluate(ctx.Body(), ctx)
+}
+
+func (o *ConditionalOperator) evaluate(json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	condition := evaluateChild(o.condition, json, ctx)
+	if condition.Error && ctx.ErrorPropagation() != jsonfilter.ErrorsAsNoMatch {
+		// Neither branch can be chosen without knowing the condition.
+		res := jsonfilter.EvaluationErrorResult(o.Name(), "condition could not be evaluated: "+condition.CauseDescription)
+		if ctx.Explaining() {
+			res.ChildOperators = []jsonfilter.EvaluationResult{condition}
+			res.Cause = &jsonfilter.Cause{Code: jsonfilter.CauseEvaluationError}
+		}
+		return res
+	}
+
+	branch := o.otherwise
+	if condition.Match {
+		branch = o.then
+	}
+	if branch == nil {
+		if ctx.Explaining() {
+			return jsonfilter.AggregateResult(o.Name(), true, []jsonfilter.EvaluationResult{condition}, "")
+		}
+		return jsonfilter.ValidResult(o.Name())
+	}
+
+	result := evaluateChild(branch, json, ctx)
+	cause := ""
+	if !result.Match {
+		cause = result.CauseDescription
+		if cause == "" {
+			cause = "child operator returned no match"
+		}
+	}
+	// The child results are only kept when explaining, so that plain evaluations do not allocate.
+	var children []jsonfilter.EvaluationResult
+	if ctx.Explaining() {
+		children = []jsonfilter.EvaluationResult{condition, result}
+	}
+	res := jsonfilter.AggregateResult(o.Name(), result.Match, children, cause)
+	res.Error = result.Error && ctx.ErrorPropagation() != jsonfilter.ErrorsAsNoMatch
+	if !res.Match && ctx.Explaining() {
+		code := jsonfilter.CauseChildMismatch
+		if res.Error {
+			code = jsonfilter.CauseEvaluationError
+		}
+		res.Cause = &jsonfilter.Cause{Code: code}
+	}
+	return res
+}
+
+// Validate ensures the condition and both branches are well defined.
+func (o *ConditionalOperator) Validate() jsonfilter.ValidationResult {
+	if o.condition == nil || o.then == nil {
+		return jsonfilter.ErrorValidationResult(o.Name(), "conditional operator requires a condition and a then branch")
+	}
+
+	children := []jsonfilter.ValidationResult{o.condition.Validate(), o.then.Validate()}
+	if o.otherwise != nil {
+		children = append(children, o.otherwise.Validate())
+	}
+	allValid := true
+	for _, child := range children {
+		if !child.Valid {
+			allValid = false
+		}
+	}
+	cause := ""
+	if !allValid {
+		cause = "child operator validation failed"
+	}
+	return jsonfilter.AggregateValidationResult(o.Name(), allValid, children, cause)
+}
@@ -1,3 +1,5 @@
-// Package logic provides the boolean operators (and/or) that combine child
-// comparison operators into executable trees with short-circuit evaluation.
+// Package logic provides the boolean operators (and/or/xor/nand/nor), the
+// threshold operators (atLeast/atMost/exactly) and the if/then/else
+// conditional that combine child comparison operators into executable trees
+// with short-circuit evaluation.
 package logic
@@ -2,26 +2,90 @@ package logic
 
 import (
 	"fmt"
+	"strconv"
 
 	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
 )
 
-// Operator represents a logical aggregation of child operators (and/or).
+// Operator represents a logical aggregation of child operators. Threshold operators additionally
+// carry the number of children that must match.
 type Operator struct {
-	typ      Type
-	children []jsonfilter.Operator
+	typ        Type
+	children   []jsonfilter.Operator
+	count      int
+	report     bool
+	tooFewMsg  string
+	tooManyMsg string
 }
 
-// NewOperator builds a new logic operator instance.
+// NewOperator builds a new logic operator instance. Threshold types must be built with
+// NewThresholdOperator.
 func NewOperator(opType Type, children []jsonfilter.Operator) (*Operator, error) {
 	if _, ok := allTypes[opType]; !ok {
 		return nil, fmt.Errorf("unsupported logic operator %q", opType)
 	}
+	if opType.IsThreshold() {
+		return nil, fmt.Errorf("logic operator %s requires a count", opType)
+	}
 	copied := make([]jsonfilter.Operator, len(children))
 	copy(copied, children)
 	return &Operator{typ: opType, children: copied}, nil
 }
 
+// NewThresholdOperator builds an atLeast, atMost or exactly operator that matches depending on how
+// many of its children match. count must lie between 0 and the number of children.
+func NewThresholdOperator(opType Type, count int, children []jsonfilter.Operator) (*Operator, error) {
+	if !opType.IsThreshold() {
+		return nil, fmt.Errorf("logic operator %q is not a threshold operator", opType)
+	}
+	if count < 0 || count > len(children) {
+		return nil, fmt.Errorf("logic operator %s count %d must be between 0 and %d", opType, count, len(children))
+	}
+	copied := make([]jsonfilter.Operator, len(children))
+	copy(copied, children)
+	n := strconv.Itoa(count)
+	return &Operator{
+		typ:        opType,
+		children:   copied,
+		count:      count,
+		tooFewMsg:  "fewer than " + n + " child operators matched",
+		tooManyMsg: "more than " + n + " child operators matched",
+	}, nil
+}
+
+// NewResultListOperator builds an and or or operator that evaluates every child, without stopping
+// early, and reports their results as ChildOperators. This is the result shape of
+// telekom/JSON-Filter, whose logic operators are built from the complete list of child results.
+func NewResultListOperator(opType Type, children []jsonfilter.Operator) (*Operator, error) {
+	if opType != And && opType != Or {
+		return nil, fmt.Errorf("logic operator %s cannot report a result list", opType)
+	}
+	op, err := NewOperator(opType, children)
+	if err != nil {
+		return nil, err
+	}
+	op.report = true
+	return op, nil
+}
+
+// MustNewResultListOperator panics when construction fails.
+func MustNewResultListOperator(opType Type, children []jsonfilter.Operator) *Operator {
+	op, err := NewResultListOperator(opType, children)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// MustNewThresholdOperator panics when construction fails.
+func MustNewThresholdOperator(opType Type, count int, children []jsonfilter.Operator) *Operator {
+	op, err := NewThresholdOperator(opType, count, children)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
 // MustNewOperator panics when construction fails.
 func MustNewOperator(opType Type, children []jsonfilter.Operator) *Operator {
 	op, err := NewOperator(opType, children)
@@ -36,25 +100,70 @@ func (o *Operator) Name() string {
 	return string(o.typ)
 }
 
+// Children returns the child operators in evaluation order.
+func (o *Operator) Children() []jsonfilter.Operator {
+	return o.children
+}
+
+// Count returns the threshold of atLeast, atMost and exactly operators.
+func (o *Operator) Count() int {
+	return o.count
+}
+
+// WithChildren returns a copy of the operator, with the same type, count and result reporting,
+// whose children are replaced.
+func (o *Operator) WithChildren(children []jsonfilter.Operator) (*Operator, error) {
+	if o.typ.IsThreshold() && o.count > len(children) {
+		return nil, fmt.Errorf("logic operator %s count %d must be between 0 and %d", o.typ, o.count, len(children))
+	}
+	copied := *o
+	copied.children = make([]jsonfilter.Operator, len(children))
+	copy(copied.children, children)
+	return &copied, nil
+}
+
 // Evaluate executes the logic operator against the provided JSON payload.
 func (o *Operator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(json, nil)
+}
+
+// EvaluateContext executes the logic operator against the documents of ctx.
+func (o *Operator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	return o.evaluate(ctx.Body(), ctx)
+}
+
+func (o *Operator) evaluate(json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
 	if len(o.children) == 0 {
-		return jsonfilter.ErrorResult(o.Name(), "logic operator requires at least one child")
+		return jsonfilter.EvaluationErrorResult(o.Name(), "logic operator requires at least one child")
+	}
+	if _, ok := allTypes[o.typ]; !ok {
+		return jsonfilter.EvaluationErrorResult(o.Name(), fmt.Sprintf("unsupported logic operator %q", o.typ))
+	}
+	if o.report || ctx.Explaining() || ctx.ErrorPropagation() != jsonfilter.ErrorsAsNoMatch {
+		return o.evaluateAll(json, ctx)
 	}
 
 	switch o.typ {
 	case And:
-		return o.evaluateAnd(json)
+		return o.evaluateAnd(json, ctx)
 	case Or:
-		return o.evaluateOr(json)
+		return o.evaluateOr(json, ctx)
+	case Xor:
+		return o.evaluateXor(json, ctx)
+	case Nand:
+		return o.evaluateNand(json, ctx)
+	case Nor:
+		return o.evaluateNor(json, ctx)
+	case AtLeast, AtMost, Exactly:
+		return o.evaluateThreshold(json, ctx)
 	default:
-		return jsonfilter.ErrorResult(o.Name(), fmt.Sprintf("unsupported logic operator %q", o.typ))
+		return jsonfilter.EvaluationErrorResult(o.Name(), fmt.Sprintf("unsupported logic operator %q", o.typ))
 	}
 }
 
-func (o *Operator) evaluateAnd(json []byte) jsonfilter.EvaluationResult {
+func (o *Operator) evaluateAnd(json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
 	for _, child := range o.children {
-		result := child.Evaluate(json)
+		result := evaluateChild(child, json, ctx)
 		if !result.Match {
 			cause := result.CauseDescription
 			if cause == "" {
@@ -66,9 +175,9 @@ func (o *Operator) evaluateAnd(json []byte) jsonfilter.EvaluationResult {
 	return jsonfilter.ValidResult(o.Name())
 }
 
-func (o *Operator) evaluateOr(json []byte) jsonfilter.EvaluationResult {
+func (o *Operator) evaluateOr(json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
 	for _, child := range o.children {
-		result := child.Evaluate(json)
+		result := evaluateChild(child, json, ctx)
 		if result.Match {
 			return jsonfilter.ValidResult(o.Name())
 		}
@@ -76,6 +185,183 @@ func (o *Operator) evaluateOr(json []byte) jsonfilter.EvaluationResult {
 	return jsonfilter.ErrorResult(o.Name(), "no child operator produced a match")
 }
 
+// evaluateAll combines the child results according to the error propagation of ctx. The results are
+// kept as ChildOperators for result-list operators and explaining evaluations, which evaluate every
+// child so that each child that did not match reports its own cause. Otherwise evaluation stops as
+// soon as the remaining children cannot change the verdict: under ErrorsAsUnknown once it is decided
+// whatever the unknown children are, under ErrorsFail at the first errored child.
+func (o *Operator) evaluateAll(json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	keep := o.report || ctx.Explaining()
+	propagation := ctx.ErrorPropagation()
+	var results []jsonfilter.EvaluationResult
+	if keep {
+		results = make([]jsonfilter.EvaluationResult, 0, len(o.children))
+	}
+	matched, errored := 0, 0
+	firstError := ""
+	for i, child := range o.children {
+		if !keep && propagation == jsonfilter.ErrorsAsUnknown && o.decided(matched, matched+errored+len(o.children)-i) {
+			break
+		}
+		result := evaluateChild(child, json, ctx)
+		if keep {
+			results = append(results, result)
+		}
+		switch {
+		case result.Match:
+			matched++
+		case result.Error:
+			if errored == 0 {
+				firstError = result.CauseDescription
+			}
+			errored++
+		}
+		if !keep && errored > 0 && propagation == jsonfilter.ErrorsFail {
+			break
+		}
+	}
+
+	var res jsonfilter.EvaluationResult
+	switch {
+	case errored > 0 && propagation == jsonfilter.ErrorsFail:
+		res = jsonfilter.EvaluationErrorResult(o.Name(), "child operator could not be evaluated: "+firstError)
+	case errored > 0 && propagation == jsonfilter.ErrorsAsUnknown && !o.decided(matched, matched+errored):
+		res = jsonfilter.EvaluationErrorResult(o.Name(), "result depends on child operators that could not be evaluated")
+	case o.satisfied(matched):
+		return jsonfilter.AggregateResult(o.Name(), true, results, "")
+	default:
+		res = jsonfilter.ErrorResult(o.Name(), o.mismatchCause(matched))
+	}
+	res.ChildOperators = results
+	if ctx.Explaining() {
+		code := jsonfilter.CauseChildMismatch
+		if res.Error {
+			code = jsonfilter.CauseEvaluationError
+		}
+		res.Cause = &jsonfilter.Cause{Code: code}
+	}
+	return res
+}
+
+// satisfied reports whether the operator matches when matched of its children match.
+func (o *Operator) satisfied(matched int) bool {
+	switch o.typ {
+	case And:
+		return matched == len(o.children)
+	case Or:
+		return matched > 0
+	case Xor:
+		return matched == 1
+	case Nand:
+		return matched < len(o.children)
+	case Nor:
+		return matched == 0
+	case AtLeast:
+		return matched >= o.count
+	case AtMost:
+		return matched <= o.count
+	default:
+		return matched == o.count
+	}
+}
+
+// decided reports whether every number of matching children between low and high yields the same
+// verdict, i.e. whether children of unknown outcome cannot change it.
+func (o *Operator) decided(low, high int) bool {
+	for n := low + 1; n <= high; n++ {
+		if o.satisfied(n) != o.satisfied(low) {
+			return false
+		}
+	}
+	return true
+}
+
+// mismatchCause describes why the operator does not match when matched of its children match.
+func (o *Operator) mismatchCause(matched int) string {
+	switch o.typ {
+	case And:
+		return "child operator returned no match"
+	case Or:
+		return "no child operator produced a match"
+	case Xor:
+		if matched > 1 {
+			return "more than one child operator produced a match"
+		}
+		return "no child operator produced a match"
+	case Nand:
+		return "every child operator produced a match"
+	case Nor:
+		return "a child operator produced a match"
+	default:
+		if matched > o.count {
+			return o.tooManyMsg
+		}
+		return o.tooFewMsg
+	}
+}
+
+// evaluateXor matches when exactly one child matches, stopping at the second match.
+func (o *Operator) evaluateXor(json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	matched := 0
+	for _, child := range o.children {
+		if evaluateChild(child, json, ctx).Match {
+			matched++
+			if matched > 1 {
+				return jsonfilter.ErrorResult(o.Name(), "more than one child operator produced a match")
+			}
+		}
+	}
+	if matched == 0 {
+		return jsonfilter.ErrorResult(o.Name(), "no child operator produced a match")
+	}
+	return jsonfilter.ValidResult(o.Name())
+}
+
+// evaluateNand matches as soon as one child does not match.
+func (o *Operator) evaluateNand(json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	for _, child := range o.children {
+		if !evaluateChild(child, json, ctx).Match {
+			return jsonfilter.ValidResult(o.Name())
+		}
+	}
+	return jsonfilter.ErrorResult(o.Name(), "every child operator produced a match")
+}
+
+// evaluateNor fails as soon as one child matches.
+func (o *Operator) evaluateNor(json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	for _, child := range o.children {
+		if evaluateChild(child, json, ctx).Match {
+			return jsonfilter.ErrorResult(o.Name(), "a child operator produced a match")
+		}
+	}
+	return jsonfilter.ValidResult(o.Name())
+}
+
+// evaluateThreshold counts matching children and stops as soon as the remaining children can no
+// longer change the outcome, which may be before the first child is evaluated.
+func (o *Operator) evaluateThreshold(json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	matched := 0
+	for i := 0; ; i++ {
+		if o.decided(matched, matched+len(o.children)-i) {
+			if o.satisfied(matched) {
+				return jsonfilter.ValidResult(o.Name())
+			}
+			return jsonfilter.ErrorResult(o.Name(), o.mismatchCause(matched))
+		}
+		if evaluateChild(o.children[i], json, ctx).Match {
+			matched++
+		}
+	}
+}
+
+// evaluateChild runs child against ctx when one is supplied and against json otherwise.
+func evaluateChild(child jsonfilter.Operator, json []byte, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	if ctx != nil {
+		return jsonfilter.EvaluateContext(child, ctx)
+	}
+	return child.Evaluate(json)
+}
+
 // Validate ensures the logic operator and child operators are well defined.
 func (o *Operator) Validate() jsonfilter.ValidationResult {
 	if len(o.children) == 0 {
@@ -83,10 +369,15 @@ func (o *Operator) Validate() jsonfilter.ValidationResult {
 	}
 
 	switch o.typ {
-	case And:
+	case And, Xor, Nand, Nor:
 		return o.validateAnd()
 	case Or:
 		return o.validateOr()
+	case AtLeast, AtMost, Exactly:
+		if o.count < 0 || o.count > len(o.children) {
+			return jsonfilter.ErrorValidationResult(o.Name(), "threshold count must be between 0 and the number of children")
+		}
+		return o.validateAnd()
 	default:
 		return jsonfilter.ErrorValidationResult(o.Name(), fmt.Sprintf("unsupported logic operator %q", o.typ))
 	}
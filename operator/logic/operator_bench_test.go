@@ -46,3 +46,48 @@ func BenchmarkOrOperatorEvaluate(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkAtLeastOperatorEvaluate(b *testing.B) {
+	children := []jsonfilter.Operator{
+		comparison.MustNewEqualOperator("foo", "bar"),
+		comparison.MustNewEqualOperator("baz", "miss"),
+		comparison.MustNewRegexOperator("baz", `^qux`),
+		comparison.MustNewEqualOperator("foo", "other"),
+	}
+
+	op, err := NewThresholdOperator(AtLeast, 2, children)
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+
+	payload := []byte(`{"foo":"bar","baz":"qux"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected atLeast to match: %#v", res)
+		}
+	}
+}
+
+func BenchmarkConditionalOperatorEvaluate(b *testing.B) {
+	condition := comparison.MustNewEqualOperator("kind", "order")
+	then := comparison.MustNewRegexOperator("id", `^ord-`)
+	otherwise := comparison.MustNewEqualOperator("id", "none")
+
+	op, err := NewConditionalOperator(condition, then, otherwise)
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+
+	payload := []byte(`{"kind":"order","id":"ord-1"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if res := op.Evaluate(payload); !res.Match {
+			b.Fatalf("expected IF to match: %#v", res)
+		}
+	}
+}
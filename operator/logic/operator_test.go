@@ -73,3 +73,294 @@ func TestOrOperatorShortCircuit(t *testing.T) {
 		t.Fatalf("expected short circuit to stop after first match, got %d calls", callCount)
 	}
 }
+
+func TestResultListOperator(t *testing.T) {
+	calls := 0
+	or := MustNewResultListOperator(Or, stubChildren(&calls, true, false))
+	res := or.Evaluate([]byte(`{}`))
+	if !res.Match || calls != 2 || len(res.ChildOperators) != 2 || res.ChildOperators[1].Match {
+		t.Fatalf("expected or to evaluate and report every child, got %d calls and %#v", calls, res)
+	}
+
+	and := MustNewResultListOperator(And, stubChildren(&calls, false, true))
+	res = and.Evaluate([]byte(`{}`))
+	if res.Match || len(res.ChildOperators) != 2 || !res.ChildOperators[1].Match {
+		t.Fatalf("unexpected and result %#v", res)
+	}
+
+	if _, err := NewResultListOperator(Xor, stubChildren(&calls, true)); err == nil {
+		t.Fatalf("expected xor to be rejected")
+	}
+}
+
+func TestExplainListsEveryChild(t *testing.T) {
+	calls := 0
+	op := MustNewThresholdOperator(AtLeast, 2, stubChildren(&calls, true, false, false))
+	res := jsonfilter.Explain(op, []byte(`{}`))
+	if res.Match || calls != 3 || len(res.ChildOperators) != 3 {
+		t.Fatalf("expected every child to be evaluated and listed, got %d calls and %#v", calls, res)
+	}
+	if res.Cause == nil || res.Cause.Code != jsonfilter.CauseChildMismatch || res.CauseDescription != "fewer than 2 child operators matched" {
+		t.Fatalf("unexpected cause %#v", res)
+	}
+
+	calls = 0
+	res = MustNewOperator(Or, stubChildren(&calls, true, false)).Evaluate([]byte(`{}`))
+	if !res.Match || calls != 1 || res.ChildOperators != nil || res.Cause != nil {
+		t.Fatalf("expected plain evaluation to short-circuit without causes, got %d calls and %#v", calls, res)
+	}
+}
+
+func TestErrorPropagation(t *testing.T) {
+	const (
+		yes = iota
+		no
+		fail
+	)
+	stubs := func(outcomes ...int) []jsonfilter.Operator {
+		children := make([]jsonfilter.Operator, 0, len(outcomes))
+		for _, outcome := range outcomes {
+			res := jsonfilter.ValidResult("stub")
+			switch outcome {
+			case no:
+				res = jsonfilter.ErrorResult("stub", "nope")
+			case fail:
+				res = jsonfilter.EvaluationErrorResult("stub", "broken")
+			}
+			children = append(children, &stubOperator{name: "stub", evalResult: res})
+		}
+		return children
+	}
+
+	cases := []struct {
+		op               jsonfilter.Operator
+		noMatch, unknown jsonfilter.Outcome
+		fail             jsonfilter.Outcome
+	}{
+		{MustNewOperator(And, stubs(yes, fail)), jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeError, jsonfilter.OutcomeError},
+		{MustNewOperator(And, stubs(no, fail)), jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeError},
+		{MustNewOperator(Or, stubs(yes, fail)), jsonfilter.OutcomeMatch, jsonfilter.OutcomeMatch, jsonfilter.OutcomeError},
+		{MustNewOperator(Or, stubs(no, fail)), jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeError, jsonfilter.OutcomeError},
+		{MustNewOperator(Nor, stubs(yes, fail)), jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeError},
+		{MustNewOperator(Xor, stubs(yes, yes, fail)), jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeError},
+		{MustNewOperator(Xor, stubs(yes, no, fail)), jsonfilter.OutcomeMatch, jsonfilter.OutcomeError, jsonfilter.OutcomeError},
+		{MustNewThresholdOperator(AtLeast, 1, stubs(yes, fail)), jsonfilter.OutcomeMatch, jsonfilter.OutcomeMatch, jsonfilter.OutcomeError},
+		{MustNewThresholdOperator(Exactly, 1, stubs(no, fail)), jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeError, jsonfilter.OutcomeError},
+		{MustNewOperator(And, stubs(yes, yes)), jsonfilter.OutcomeMatch, jsonfilter.OutcomeMatch, jsonfilter.OutcomeMatch},
+	}
+	for i, tc := range cases {
+		for propagation, want := range map[jsonfilter.ErrorPropagation]jsonfilter.Outcome{
+			jsonfilter.ErrorsAsNoMatch: tc.noMatch,
+			jsonfilter.ErrorsAsUnknown: tc.unknown,
+			jsonfilter.ErrorsFail:      tc.fail,
+		} {
+			ctx := jsonfilter.NewContext([]byte(`{}`)).WithErrorPropagation(propagation)
+			if got := jsonfilter.EvaluateContext(tc.op, ctx).Outcome(); got != want {
+				t.Fatalf("case %d with propagation %d: expected %s, got %s", i, propagation, want, got)
+			}
+		}
+		if got := tc.op.Evaluate([]byte(`{}`)).Outcome(); got != tc.noMatch {
+			t.Fatalf("case %d: expected Evaluate to count errors as no match, got %s", i, got)
+		}
+	}
+
+	if res := (&Operator{typ: And}).Evaluate([]byte(`{}`)); res.Outcome() != jsonfilter.OutcomeError {
+		t.Fatalf("expected a logic operator without children to report an error, got %#v", res)
+	}
+
+	cond := MustNewConditionalOperator(stubs(fail)[0], stubs(yes)[0], stubs(yes)[0])
+	if res := cond.Evaluate([]byte(`{}`)); res.Outcome() != jsonfilter.OutcomeMatch {
+		t.Fatalf("expected errored condition to select else, got %#v", res)
+	}
+	ctx := jsonfilter.NewContext([]byte(`{}`)).WithErrorPropagation(jsonfilter.ErrorsAsUnknown)
+	if res := jsonfilter.EvaluateContext(cond, ctx); res.Outcome() != jsonfilter.OutcomeError {
+		t.Fatalf("expected errored condition to propagate, got %#v", res)
+	}
+}
+
+func TestErrorPropagationStopsEarly(t *testing.T) {
+	calls := 0
+	broken := &stubOperator{name: "broken", evalResult: jsonfilter.EvaluationErrorResult("broken", "broken"), calls: &calls}
+	cases := []struct {
+		op          jsonfilter.Operator
+		propagation jsonfilter.ErrorPropagation
+		want        jsonfilter.Outcome
+		calls       int
+	}{
+		{MustNewOperator(And, append(stubChildren(&calls, false, true), broken)), jsonfilter.ErrorsAsUnknown, jsonfilter.OutcomeNoMatch, 1},
+		{MustNewOperator(Or, append([]jsonfilter.Operator{broken}, stubChildren(&calls, true, false)...)), jsonfilter.ErrorsAsUnknown, jsonfilter.OutcomeMatch, 2},
+		{MustNewThresholdOperator(AtMost, 2, stubChildren(&calls, true, true)), jsonfilter.ErrorsAsUnknown, jsonfilter.OutcomeMatch, 0},
+		{MustNewOperator(And, append([]jsonfilter.Operator{broken}, stubChildren(&calls, true, true)...)), jsonfilter.ErrorsFail, jsonfilter.OutcomeError, 1},
+		{MustNewOperator(Or, append(stubChildren(&calls, true), broken)), jsonfilter.ErrorsFail, jsonfilter.OutcomeError, 2},
+	}
+	for i, tc := range cases {
+		calls = 0
+		ctx := jsonfilter.NewContext([]byte(`{}`)).WithErrorPropagation(tc.propagation)
+		if got := jsonfilter.EvaluateContext(tc.op, ctx).Outcome(); got != tc.want {
+			t.Fatalf("case %d: expected %s, got %s", i, tc.want, got)
+		}
+		if calls != tc.calls {
+			t.Fatalf("case %d: expected %d child evaluations, got %d", i, tc.calls, calls)
+		}
+		if tc.want == jsonfilter.OutcomeError {
+			continue // the error cause quotes the child's cause
+		}
+		if allocs := testing.AllocsPerRun(100, func() { jsonfilter.EvaluateContext(tc.op, ctx) }); allocs != 0 {
+			t.Fatalf("case %d: expected no allocations, got %v", i, allocs)
+		}
+	}
+
+	calls = 0
+	ctx := jsonfilter.NewContext([]byte(`{}`)).WithErrorPropagation(jsonfilter.ErrorsAsUnknown).WithExplain()
+	if res := jsonfilter.EvaluateContext(cases[0].op, ctx); len(res.ChildOperators) != 3 || calls != 3 {
+		t.Fatalf("expected explaining to evaluate every child, got %d calls: %#v", calls, res)
+	}
+}
+
+func stubChildren(calls *int, matches ...bool) []jsonfilter.Operator {
+	children := make([]jsonfilter.Operator, 0, len(matches))
+	for _, match := range matches {
+		res := jsonfilter.ErrorResult("stub", "nope")
+		if match {
+			res = jsonfilter.ValidResult("stub")
+		}
+		children = append(children, &stubOperator{name: "stub", evalResult: res, calls: calls})
+	}
+	return children
+}
+
+func TestBooleanOperators(t *testing.T) {
+	cases := []struct {
+		typ     Type
+		matches []bool
+		want    bool
+		calls   int
+	}{
+		{Xor, []bool{false, true, false}, true, 3},
+		{Xor, []bool{true, true, false}, false, 2},
+		{Xor, []bool{false, false}, false, 2},
+		{Nand, []bool{true, false, true}, true, 2},
+		{Nand, []bool{true, true}, false, 2},
+		{Nor, []bool{false, true, false}, false, 2},
+		{Nor, []bool{false, false}, true, 2},
+	}
+	for _, tc := range cases {
+		calls := 0
+		op := MustNewOperator(tc.typ, stubChildren(&calls, tc.matches...))
+		res := op.Evaluate([]byte(`{}`))
+		if res.Match != tc.want {
+			t.Fatalf("%s %v: expected match=%v, got %#v", tc.typ, tc.matches, tc.want, res)
+		}
+		if calls != tc.calls {
+			t.Fatalf("%s %v: expected %d child evaluations, got %d", tc.typ, tc.matches, tc.calls, calls)
+		}
+	}
+}
+
+func TestThresholdOperators(t *testing.T) {
+	cases := []struct {
+		typ     Type
+		count   int
+		matches []bool
+		want    bool
+		calls   int
+		cause   string
+	}{
+		{AtLeast, 2, []bool{true, true, false, false}, true, 2, ""},
+		{AtLeast, 2, []bool{false, false, false, true}, false, 3, "fewer than 2 child operators matched"},
+		{AtMost, 1, []bool{true, true, false}, false, 2, "more than 1 child operators matched"},
+		{AtMost, 1, []bool{false, false, true}, true, 2, ""},
+		{Exactly, 1, []bool{false, true, false}, true, 3, ""},
+		{Exactly, 1, []bool{true, true, false}, false, 2, "more than 1 child operators matched"},
+		{Exactly, 2, []bool{false, false, true}, false, 2, "fewer than 2 child operators matched"},
+		{AtLeast, 0, []bool{false, true}, true, 0, ""},
+		{AtMost, 2, []bool{true, true}, true, 0, ""},
+		{AtLeast, 2, []bool{true, true}, true, 2, ""},
+		{Exactly, 0, []bool{false, true}, false, 2, "more than 0 child operators matched"},
+	}
+	for _, tc := range cases {
+		calls := 0
+		op := MustNewThresholdOperator(tc.typ, tc.count, stubChildren(&calls, tc.matches...))
+		res := op.Evaluate([]byte(`{}`))
+		if res.Match != tc.want || res.CauseDescription != tc.cause {
+			t.Fatalf("%s(%d) %v: unexpected result %#v", tc.typ, tc.count, tc.matches, res)
+		}
+		if calls != tc.calls {
+			t.Fatalf("%s(%d) %v: expected %d child evaluations, got %d", tc.typ, tc.count, tc.matches, tc.calls, calls)
+		}
+	}
+}
+
+func TestThresholdOperatorConstruction(t *testing.T) {
+	children := stubChildren(nil, true, false)
+	if _, err := NewOperator(AtLeast, children); err == nil {
+		t.Fatalf("expected threshold type without count to be rejected")
+	}
+	if _, err := NewThresholdOperator(Xor, 1, children); err == nil {
+		t.Fatalf("expected non-threshold type to be rejected")
+	}
+	if _, err := NewThresholdOperator(AtLeast, 3, children); err == nil {
+		t.Fatalf("expected count above child count to be rejected")
+	}
+	if _, err := NewThresholdOperator(AtMost, -1, children); err == nil {
+		t.Fatalf("expected negative count to be rejected")
+	}
+}
+
+func TestConditionalOperatorBranches(t *testing.T) {
+	calls := 0
+	condition := &stubOperator{name: "cond", evalResult: jsonfilter.ValidResult("cond"), calls: &calls}
+	then := &stubOperator{name: "then", evalResult: jsonfilter.ErrorResult("then", "then failed"), calls: &calls}
+	otherwise := &stubOperator{name: "else", evalResult: jsonfilter.ValidResult("else"), calls: &calls}
+
+	op := MustNewConditionalOperator(condition, then, otherwise)
+	res := op.Evaluate([]byte(`{}`))
+	if res.Match || res.CauseDescription != "then failed" {
+		t.Fatalf("expected then branch failure to propagate: %#v", res)
+	}
+	if calls != 2 {
+		t.Fatalf("expected condition and one branch to run, got %d calls", calls)
+	}
+	if res.ChildOperators != nil {
+		t.Fatalf("expected no trace without explaining: %#v", res.ChildOperators)
+	}
+	res = jsonfilter.Explain(op, []byte(`{}`))
+	if len(res.ChildOperators) != 2 || !res.ChildOperators[0].Match || res.ChildOperators[1].OperatorName != "then" {
+		t.Fatalf("expected trace to record the then branch: %#v", res.ChildOperators)
+	}
+
+	condition.evalResult = jsonfilter.ErrorResult("cond", "nope")
+	res = jsonfilter.Explain(op, []byte(`{}`))
+	if !res.Match || res.ChildOperators[1].OperatorName != "else" {
+		t.Fatalf("expected else branch to run: %#v", res)
+	}
+
+	noElse := MustNewConditionalOperator(condition, then, nil)
+	if res = noElse.Evaluate([]byte(`{}`)); !res.Match {
+		t.Fatalf("expected false condition without else to match: %#v", res)
+	}
+	if res = jsonfilter.Explain(noElse, []byte(`{}`)); !res.Match || len(res.ChildOperators) != 1 {
+		t.Fatalf("expected the trace to record the condition: %#v", res)
+	}
+
+	if _, err := NewConditionalOperator(condition, nil, nil); err == nil {
+		t.Fatalf("expected missing then branch to be rejected")
+	}
+}
+
+func TestConditionalOperatorEvaluateDoesNotAllocate(t *testing.T) {
+	condition := &stubOperator{name: "cond", evalResult: jsonfilter.ValidResult("cond")}
+	then := &stubOperator{name: "then", evalResult: jsonfilter.ErrorResult("then", "then failed")}
+	otherwise := &stubOperator{name: "else", evalResult: jsonfilter.ValidResult("else")}
+	op := MustNewConditionalOperator(condition, then, otherwise)
+	payload := []byte(`{}`)
+
+	for _, taken := range []bool{true, false} {
+		if !taken {
+			condition.evalResult = jsonfilter.ErrorResult("cond", "nope")
+		}
+		if allocs := testing.AllocsPerRun(100, func() { op.Evaluate(payload) }); allocs != 0 {
+			t.Fatalf("expected no allocations, got %v", allocs)
+		}
+	}
+}
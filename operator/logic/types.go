@@ -1,18 +1,41 @@
 package logic
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 // Type enumerates supported logic operators.
 type Type string
 
 const (
-	And Type = "and"
-	Or  Type = "or"
+	And  Type = "and"
+	Or   Type = "or"
+	Xor  Type = "xor"
+	Nand Type = "nand"
+	Nor  Type = "nor"
+
+	// Threshold operators carry a count alongside their children. Names are lower case because the
+	// serde parser matches operator names case-insensitively.
+	AtLeast Type = "atleast"
+	AtMost  Type = "atmost"
+	Exactly Type = "exactly"
 )
 
 var allTypes = map[Type]struct{}{
-	And: {},
-	Or:  {},
+	And:     {},
+	Or:      {},
+	Xor:     {},
+	Nand:    {},
+	Nor:     {},
+	AtLeast: {},
+	AtMost:  {},
+	Exactly: {},
+}
+
+// IsThreshold reports whether the operator type requires a count.
+func (t Type) IsThreshold() bool {
+	return t == AtLeast || t == AtMost || t == Exactly
 }
 
 // ParseType validates the provided operator name.
@@ -24,6 +47,17 @@ func ParseType(op string) (Type, error) {
 	return t, nil
 }
 
+// Names returns the names of the boolean and threshold operators in sorted order. The conditional
+// operator If is parsed separately and not included.
+func Names() []string {
+	names := make([]string, 0, len(allTypes))
+	for t := range allTypes {
+		names = append(names, string(t))
+	}
+	sort.Strings(names)
+	return names
+}
+
 // MustParseType panics if the provided operator name is invalid.
 func MustParseType(op string) Type {
 	t, err := ParseType(op)
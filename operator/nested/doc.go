@@ -0,0 +1,5 @@
+// Package nested provides operators that decode a document embedded in a JSON
+// value (an escaped JSON string or base64-encoded JSON) and evaluate a child
+// operator tree against the decoded payload, and the elemMatch operator that
+// evaluates a child tree against each element of an array.
+package nested
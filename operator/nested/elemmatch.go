@@ -0,0 +1,145 @@
+package nested
+
+import (
+	"fmt"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// ElemMatchName is the identifier reported by ElemMatchOperator results.
+const ElemMatchName = "elemmatch"
+
+// ElemMatchOperator evaluates a child operator tree against every element of the array at a JSON
+// path and matches when at least one element matches. Scalar elements are addressed with "@this".
+type ElemMatchOperator struct {
+	jsonPath        string
+	child           jsonfilter.Operator
+	pathNotFoundMsg string
+	notArrayMsg     string
+	erroredMsg      string
+	noMatchMsg      string
+}
+
+// NewElemMatchOperator builds an operator matching arrays with at least one element satisfying child.
+func NewElemMatchOperator(jsonPath string, child jsonfilter.Operator) (*ElemMatchOperator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if child == nil {
+		return nil, fmt.Errorf("elemMatch operator requires a child operator")
+	}
+	return &ElemMatchOperator{
+		jsonPath:        jsonPath,
+		child:           child,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		notArrayMsg:     "value at json path " + jsonPath + " is not an array",
+		erroredMsg:      "an element at json path " + jsonPath + " could not be evaluated",
+		noMatchMsg:      "no element at json path " + jsonPath + " matches",
+	}, nil
+}
+
+// MustNewElemMatchOperator panics when construction fails.
+func MustNewElemMatchOperator(jsonPath string, child jsonfilter.Operator) *ElemMatchOperator {
+	op, err := NewElemMatchOperator(jsonPath, child)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+// Name returns the identifier of the elemMatch operator.
+func (o *ElemMatchOperator) Name() string {
+	return ElemMatchName
+}
+
+// Field returns the JSON path of the array.
+func (o *ElemMatchOperator) Field() string {
+	return o.jsonPath
+}
+
+// Child returns the operator evaluated against each element.
+func (o *ElemMatchOperator) Child() jsonfilter.Operator {
+	return o.child
+}
+
+// Evaluate evaluates the child operator against each element of the array until one matches.
+func (o *ElemMatchOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath), nil)
+}
+
+// EvaluateContext resolves the path against the documents of ctx. Each element becomes the body
+// while the other context documents stay addressable.
+func (o *ElemMatchOperator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	doc, path := ctx.Resolve(o.jsonPath)
+	return o.evaluate(getJSONResult(doc, path), ctx)
+}
+
+func (o *ElemMatchOperator) evaluate(actual gjson.Result, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return explain(ctx, jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg), jsonfilter.CausePathNotFound, o.jsonPath, actual)
+	}
+	if !actual.IsArray() {
+		return explain(ctx, jsonfilter.ErrorResult(o.Name(), o.notArrayMsg), jsonfilter.CauseTypeMismatch, o.jsonPath, actual)
+	}
+
+	// Like or over the elements: errored elements count as non-matching unless ctx propagates
+	// errors, and ErrorsFail needs every element to tell whether one of them errored.
+	propagation := ctx.ErrorPropagation()
+	found, errored := false, false
+	var results []jsonfilter.EvaluationResult
+	actual.ForEach(func(_, element gjson.Result) bool {
+		raw := stringBytes(element.Raw)
+		var result jsonfilter.EvaluationResult
+		if ctx != nil {
+			result = jsonfilter.EvaluateContext(o.child, ctx.WithBody(raw))
+		} else {
+			result = o.child.Evaluate(raw)
+		}
+		found = found || result.Match
+		errored = errored || result.Error
+		if ctx.Explaining() {
+			results = append(results, result)
+		}
+		if propagation == jsonfilter.ErrorsFail {
+			return !errored
+		}
+		return !found
+	})
+
+	var res jsonfilter.EvaluationResult
+	switch {
+	case errored && (propagation == jsonfilter.ErrorsFail || (!found && propagation == jsonfilter.ErrorsAsUnknown)):
+		res = jsonfilter.EvaluationErrorResult(o.Name(), o.erroredMsg)
+	case found:
+		return jsonfilter.ValidResult(o.Name())
+	default:
+		res = jsonfilter.ErrorResult(o.Name(), o.noMatchMsg)
+	}
+	if ctx.Explaining() {
+		// Every element up to the verdict was evaluated, so results lists why each one failed.
+		res.ChildOperators = results
+		code := jsonfilter.CauseChildMismatch
+		if res.Error {
+			code = jsonfilter.CauseEvaluationError
+		}
+		res.Cause = &jsonfilter.Cause{Code: code, Path: o.jsonPath}
+	}
+	return res
+}
+
+// Validate ensures the elemMatch operator and its child are well defined.
+func (o *ElemMatchOperator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if o.child == nil {
+		return jsonfilter.ErrorValidationResult(o.Name(), "elemMatch operator requires a child operator")
+	}
+	child := o.child.Validate()
+	cause := ""
+	if !child.Valid {
+		cause = "child operator validation failed"
+	}
+	return jsonfilter.AggregateValidationResult(o.Name(), child.Valid, []jsonfilter.ValidationResult{child}, cause)
+}
@@ -0,0 +1,35 @@
+package nested
+
+import (
+	"unsafe"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// getJSONResult mirrors the comparison package helper: it resolves a JSON path with gjson.Get on an
+// unsafe string view of the payload to avoid the copies made by gjson.GetBytes. The result is only used
+// within Evaluate.
+func getJSONResult(payload []byte, path string) gjson.Result {
+	if len(payload) == 0 {
+		return gjson.Result{}
+	}
+	jsonStr := *(*string)(unsafe.Pointer(&payload))
+	return gjson.Get(jsonStr, path)
+}
+
+// stringBytes returns a read-only byte view of s without copying.
+func stringBytes(s string) []byte {
+	if s == "" {
+		return nil
+	}
+	return unsafe.Slice(unsafe.StringData(s), len(s))
+}
+
+// explain attaches a Cause to res when ctx explains its evaluations.
+func explain(ctx *jsonfilter.Context, res jsonfilter.EvaluationResult, code jsonfilter.CauseCode, path string, actual gjson.Result) jsonfilter.EvaluationResult {
+	if ctx.Explaining() {
+		res.Cause = jsonfilter.NewCause(code, path, nil, actual.Raw)
+	}
+	return res
+}
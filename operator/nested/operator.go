@@ -0,0 +1,172 @@
+package nested
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+// OperatorName is the identifier reported by Operator results.
+const OperatorName = "nested"
+
+// Operator decodes the document embedded at a JSON path and evaluates a child operator tree against
+// it. The value is decoded once per evaluation, so every operator in the child tree shares the same
+// decoded payload.
+type Operator struct {
+	jsonPath        string
+	decoding        Decoding
+	child           jsonfilter.Operator
+	pathNotFoundMsg string
+	decodeFailedMsg string
+}
+
+// NewOperator builds a nested operator evaluating child against the decoded value at jsonPath.
+func NewOperator(jsonPath string, decoding Decoding, child jsonfilter.Operator) (*Operator, error) {
+	if err := jsonfilter.CheckPath(jsonPath); err != nil {
+		return nil, err
+	}
+	if _, ok := allDecodings[decoding]; !ok {
+		return nil, fmt.Errorf("unsupported decoding %q", decoding)
+	}
+	if child == nil {
+		return nil, fmt.Errorf("nested operator requires a child operator")
+	}
+	return &Operator{
+		jsonPath:        jsonPath,
+		decoding:        decoding,
+		child:           child,
+		pathNotFoundMsg: "json path " + jsonPath + " not found",
+		decodeFailedMsg: "value at json path " + jsonPath + " is not " + describeDecoding(decoding),
+	}, nil
+}
+
+// MustNewOperator panics when construction fails.
+func MustNewOperator(jsonPath string, decoding Decoding, child jsonfilter.Operator) *Operator {
+	op, err := NewOperator(jsonPath, decoding, child)
+	if err != nil {
+		panic(err)
+	}
+	return op
+}
+
+func describeDecoding(d Decoding) string {
+	if d == Base64JSON {
+		return "base64-encoded JSON"
+	}
+	return "embedded JSON"
+}
+
+// Name returns the identifier of the nested operator.
+func (o *Operator) Name() string {
+	return OperatorName
+}
+
+// Field returns the JSON path of the embedded document.
+func (o *Operator) Field() string {
+	return o.jsonPath
+}
+
+// Decoding returns how the embedded document is decoded.
+func (o *Operator) Decoding() Decoding {
+	return o.decoding
+}
+
+// Child returns the operator evaluated against the decoded document.
+func (o *Operator) Child() jsonfilter.Operator {
+	return o.child
+}
+
+// Evaluate decodes the embedded document and evaluates the child operator against it.
+func (o *Operator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	return o.evaluate(getJSONResult(json, o.jsonPath), nil)
+}
+
+// EvaluateContext resolves the path against the documents of ctx. The child is evaluated with the
+// decoded document as body while the other context documents stay addressable.
+func (o *Operator) EvaluateContext(ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	doc, path := ctx.Resolve(o.jsonPath)
+	return o.evaluate(getJSONResult(doc, path), ctx)
+}
+
+func (o *Operator) evaluate(actual gjson.Result, ctx *jsonfilter.Context) jsonfilter.EvaluationResult {
+	if !actual.Exists() {
+		return explain(ctx, jsonfilter.ErrorResult(o.Name(), o.pathNotFoundMsg), jsonfilter.CausePathNotFound, o.jsonPath, actual)
+	}
+
+	decoded, ok := o.decode(actual)
+	if !ok {
+		return explain(ctx, jsonfilter.EvaluationErrorResult(o.Name(), o.decodeFailedMsg), jsonfilter.CauseDecodeFailed, o.jsonPath, actual)
+	}
+
+	var result jsonfilter.EvaluationResult
+	if ctx != nil {
+		result = jsonfilter.EvaluateContext(o.child, ctx.WithBody(decoded))
+	} else {
+		result = o.child.Evaluate(decoded)
+	}
+	if !result.Match {
+		cause := result.CauseDescription
+		if cause == "" {
+			cause = "child operator returned no match"
+		}
+		res := jsonfilter.ErrorResult(o.Name(), cause)
+		res.Error = result.Error && ctx.ErrorPropagation() != jsonfilter.ErrorsAsNoMatch
+		if ctx.Explaining() {
+			res.ChildOperators = []jsonfilter.EvaluationResult{result}
+			code := jsonfilter.CauseChildMismatch
+			if res.Error {
+				code = jsonfilter.CauseEvaluationError
+			}
+			res.Cause = &jsonfilter.Cause{Code: code, Path: o.jsonPath}
+		}
+		return res
+	}
+	return jsonfilter.ValidResult(o.Name())
+}
+
+// decode returns the embedded document. JSON strings are unescaped by gjson; objects and arrays that
+// are already inline are used as-is so that the same filter works for both representations.
+func (o *Operator) decode(actual gjson.Result) ([]byte, bool) {
+	switch o.decoding {
+	case Base64JSON:
+		if actual.Type != gjson.String {
+			return nil, false
+		}
+		for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+			if decoded, err := enc.DecodeString(actual.Str); err == nil && gjson.ValidBytes(decoded) {
+				return decoded, true
+			}
+		}
+		return nil, false
+	default:
+		switch {
+		case actual.Type == gjson.String:
+			if !gjson.Valid(actual.Str) {
+				return nil, false
+			}
+			return stringBytes(actual.Str), true
+		case actual.IsObject(), actual.IsArray():
+			return stringBytes(actual.Raw), true
+		default:
+			return nil, false
+		}
+	}
+}
+
+// Validate ensures the nested operator and its child are well defined.
+func (o *Operator) Validate() jsonfilter.ValidationResult {
+	if o.jsonPath == "" {
+		return jsonfilter.ErrorValidationResult(o.Name(), "json path must not be empty")
+	}
+	if o.child == nil {
+		return jsonfilter.ErrorValidationResult(o.Name(), "nested operator requires a child operator")
+	}
+	child := o.child.Validate()
+	cause := ""
+	if !child.Valid {
+		cause = "child operator validation failed"
+	}
+	return jsonfilter.AggregateValidationResult(o.Name(), child.Valid, []jsonfilter.ValidationResult{child}, cause)
+}
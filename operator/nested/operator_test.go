@@ -0,0 +1,191 @@
+package nested
+
+import (
+	"encoding/base64"
+	"testing"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+)
+
+type countingOperator struct {
+	calls    int
+	payloads []string
+	match    bool
+}
+
+func (c *countingOperator) Name() string { return "counting" }
+
+func (c *countingOperator) Evaluate(json []byte) jsonfilter.EvaluationResult {
+	c.calls++
+	c.payloads = append(c.payloads, string(json))
+	if c.match {
+		return jsonfilter.ValidResult("counting")
+	}
+	return jsonfilter.ErrorResult("counting", "child miss")
+}
+
+func (c *countingOperator) Validate() jsonfilter.ValidationResult {
+	return jsonfilter.ValidValidationResult("counting")
+}
+
+func TestOperatorDecodesEscapedJSON(t *testing.T) {
+	child := &countingOperator{match: true}
+	op := MustNewOperator("data", JSON, child)
+
+	res := op.Evaluate([]byte(`{"data":"{\"id\":1,\"name\":\"x\"}"}`))
+	if !res.Match {
+		t.Fatalf("expected nested match: %#v", res)
+	}
+	if child.calls != 1 || child.payloads[0] != `{"id":1,"name":"x"}` {
+		t.Fatalf("expected child to see decoded payload once, got %v", child.payloads)
+	}
+
+	inline := op.Evaluate([]byte(`{"data":{"id":2}}`))
+	if !inline.Match || child.payloads[1] != `{"id":2}` {
+		t.Fatalf("expected inline object to be evaluated as-is: %#v %v", inline, child.payloads)
+	}
+}
+
+func TestOperatorDecodesBase64JSON(t *testing.T) {
+	child := &countingOperator{match: true}
+	op := MustNewOperator("data", Base64JSON, child)
+
+	for _, encoded := range []string{
+		base64.StdEncoding.EncodeToString([]byte(`{"id":1}`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`{"id":1}`)),
+	} {
+		if res := op.Evaluate([]byte(`{"data":"` + encoded + `"}`)); !res.Match {
+			t.Fatalf("expected base64 payload %q to decode: %#v", encoded, res)
+		}
+	}
+	if child.payloads[0] != `{"id":1}` {
+		t.Fatalf("unexpected decoded payload %q", child.payloads[0])
+	}
+}
+
+func TestOperatorFailures(t *testing.T) {
+	child := &countingOperator{}
+	op := MustNewOperator("data", JSON, child)
+
+	cases := map[string]string{
+		`{}`:                    "json path data not found",
+		`{"data":"not json"}`:   "value at json path data is not embedded JSON",
+		`{"data":42}`:           "value at json path data is not embedded JSON",
+		`{"data":"{\"id\":1}"}`: "child miss",
+	}
+	for payload, cause := range cases {
+		res := op.Evaluate([]byte(payload))
+		if res.Match || res.CauseDescription != cause {
+			t.Fatalf("%s: unexpected result %#v", payload, res)
+		}
+	}
+
+	b64 := MustNewOperator("data", Base64JSON, child)
+	if res := b64.Evaluate([]byte(`{"data":"!!!"}`)); res.Match || res.CauseDescription != "value at json path data is not base64-encoded JSON" {
+		t.Fatalf("unexpected result %#v", res)
+	}
+
+	if _, err := NewOperator("data", "xml", child); err == nil {
+		t.Fatalf("expected unsupported decoding to be rejected")
+	}
+	if _, err := NewOperator("data", JSON, nil); err == nil {
+		t.Fatalf("expected missing child to be rejected")
+	}
+	if _, err := NewOperator("data.", JSON, child); err == nil {
+		t.Fatalf("expected malformed path to be rejected")
+	}
+}
+
+func TestElemMatchOperator(t *testing.T) {
+	child := &countingOperator{match: true}
+	op := MustNewElemMatchOperator("items", child)
+
+	if res := op.Evaluate([]byte(`{"items":[{"id":1},2]}`)); !res.Match {
+		t.Fatalf("expected elemMatch match: %#v", res)
+	}
+	if child.calls != 1 || child.payloads[0] != `{"id":1}` {
+		t.Fatalf("expected evaluation to stop at the first matching element, got %v", child.payloads)
+	}
+
+	miss := MustNewElemMatchOperator("items", &countingOperator{})
+	cases := map[string]string{
+		`{}`:              "json path items not found",
+		`{"items":{}}`:    "value at json path items is not an array",
+		`{"items":[1,2]}`: "no element at json path items matches",
+		`{"items":[]}`:    "no element at json path items matches",
+	}
+	for payload, cause := range cases {
+		res := miss.Evaluate([]byte(payload))
+		if res.Match || res.CauseDescription != cause {
+			t.Fatalf("%s: unexpected result %#v", payload, res)
+		}
+	}
+
+	ctx := jsonfilter.NewContext([]byte(`{"items":["a","b"]}`))
+	seen := &countingOperator{}
+	jsonfilter.EvaluateContext(MustNewElemMatchOperator("items", seen), ctx)
+	if len(seen.payloads) != 2 || seen.payloads[1] != `"b"` {
+		t.Fatalf("expected each element as body, got %v", seen.payloads)
+	}
+}
+
+func TestExplainNestedCauses(t *testing.T) {
+	miss := MustNewElemMatchOperator("items", &countingOperator{})
+	cases := map[string]jsonfilter.CauseCode{
+		`{}`:              jsonfilter.CausePathNotFound,
+		`{"items":{}}`:    jsonfilter.CauseTypeMismatch,
+		`{"items":[1,2]}`: jsonfilter.CauseChildMismatch,
+	}
+	for payload, code := range cases {
+		res := jsonfilter.Explain(miss, []byte(payload))
+		if res.Match || res.Cause == nil || res.Cause.Code != code || res.Cause.Path != "items" {
+			t.Fatalf("%s: unexpected result %#v", payload, res)
+		}
+	}
+	if res := jsonfilter.Explain(miss, []byte(`{"items":[1,2]}`)); len(res.ChildOperators) != 2 {
+		t.Fatalf("expected a result per element, got %#v", res.ChildOperators)
+	}
+
+	op := MustNewOperator("body", JSON, &countingOperator{})
+	res := jsonfilter.Explain(op, []byte(`{"body":"{not json"}`))
+	if res.Cause == nil || res.Cause.Code != jsonfilter.CauseDecodeFailed || res.Cause.Actual != "{not json" {
+		t.Fatalf("unexpected decode failure %#v", res.Cause)
+	}
+	res = jsonfilter.Explain(op, []byte(`{"body":"{}"}`))
+	if res.Cause == nil || res.Cause.Code != jsonfilter.CauseChildMismatch || len(res.ChildOperators) != 1 {
+		t.Fatalf("unexpected child failure %#v", res)
+	}
+}
+
+func TestErrorPropagation(t *testing.T) {
+	op := MustNewOperator("data", JSON, &countingOperator{match: true})
+	if res := op.Evaluate([]byte(`{"data":"not json"}`)); res.Outcome() != jsonfilter.OutcomeError {
+		t.Fatalf("expected undecodable document to be an error, got %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{}`)); res.Outcome() != jsonfilter.OutcomeNoMatch {
+		t.Fatalf("expected missing document to be a non-match, got %#v", res)
+	}
+
+	elem := MustNewElemMatchOperator("items", MustNewOperator("@this", JSON, &countingOperator{match: true}))
+	cases := []struct {
+		payload                 string
+		noMatch, unknown, fails jsonfilter.Outcome
+	}{
+		{`{"items":["not json","{}"]}`, jsonfilter.OutcomeMatch, jsonfilter.OutcomeMatch, jsonfilter.OutcomeError},
+		{`{"items":["{}","not json"]}`, jsonfilter.OutcomeMatch, jsonfilter.OutcomeMatch, jsonfilter.OutcomeError},
+		{`{"items":["not json"]}`, jsonfilter.OutcomeNoMatch, jsonfilter.OutcomeError, jsonfilter.OutcomeError},
+		{`{"items":["{}"]}`, jsonfilter.OutcomeMatch, jsonfilter.OutcomeMatch, jsonfilter.OutcomeMatch},
+	}
+	for _, tc := range cases {
+		for propagation, want := range map[jsonfilter.ErrorPropagation]jsonfilter.Outcome{
+			jsonfilter.ErrorsAsNoMatch: tc.noMatch,
+			jsonfilter.ErrorsAsUnknown: tc.unknown,
+			jsonfilter.ErrorsFail:      tc.fails,
+		} {
+			ctx := jsonfilter.NewContext([]byte(tc.payload)).WithErrorPropagation(propagation)
+			if got := jsonfilter.EvaluateContext(elem, ctx).Outcome(); got != want {
+				t.Fatalf("%s with propagation %d: expected %s, got %s", tc.payload, propagation, want, got)
+			}
+		}
+	}
+}
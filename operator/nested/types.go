@@ -0,0 +1,34 @@
+package nested
+
+import "fmt"
+
+// Decoding enumerates how an embedded document is decoded.
+type Decoding string
+
+const (
+	JSON       Decoding = "json"
+	Base64JSON Decoding = "base64json"
+)
+
+var allDecodings = map[Decoding]struct{}{
+	JSON:       {},
+	Base64JSON: {},
+}
+
+// ParseDecoding validates and returns the corresponding Decoding.
+func ParseDecoding(name string) (Decoding, error) {
+	d := Decoding(name)
+	if _, ok := allDecodings[d]; !ok {
+		return "", fmt.Errorf("decoding %q is not supported", name)
+	}
+	return d, nil
+}
+
+// MustParseDecoding panics when the provided decoding name is not supported.
+func MustParseDecoding(name string) Decoding {
+	d, err := ParseDecoding(name)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
@@ -0,0 +1,80 @@
+package jsonfilter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CheckPath reports gjson path syntax that can never select a value: empty path components, queries,
+// multipaths, modifier arguments or strings that are not closed, and trailing escapes. gjson resolves
+// such paths to a missing value, so operators reject them at construction time instead of reporting a
+// non-match for every payload.
+func CheckPath(path string) error {
+	if path == "" {
+		return errors.New("json path must not be empty")
+	}
+	rest := path
+	if strings.HasPrefix(rest, "..") {
+		// JSON Lines paths start with `..`.
+		rest = rest[2:]
+	}
+	var open []byte
+	inString := false
+	start := true
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case c == '\\':
+			if i+1 == len(rest) {
+				return fmt.Errorf("json path %s ends with an escape", path)
+			}
+			i++
+		case inString:
+			if c == '"' {
+				inString = false
+			}
+		case len(open) > 0:
+			switch c {
+			case '"':
+				inString = true
+			case '(', '[', '{':
+				open = append(open, closing(c))
+			case ')', ']', '}':
+				if c != open[len(open)-1] {
+					return fmt.Errorf("json path %s has an unbalanced %c", path, c)
+				}
+				open = open[:len(open)-1]
+			}
+		case c == '.' || c == '|':
+			if start {
+				return fmt.Errorf("json path %s has an empty component", path)
+			}
+			start = true
+			continue
+		case c == '(' && i > 0 && rest[i-1] == '#',
+			(c == '[' || c == '{') && (start || rest[i-1] == ':'):
+			open = append(open, closing(c))
+		}
+		start = false
+	}
+	switch {
+	case inString:
+		return fmt.Errorf("json path %s has an unterminated string", path)
+	case len(open) > 0:
+		return fmt.Errorf("json path %s is missing a closing %c", path, open[len(open)-1])
+	case start:
+		return fmt.Errorf("json path %s has an empty component", path)
+	}
+	return nil
+}
+
+func closing(c byte) byte {
+	switch c {
+	case '(':
+		return ')'
+	case '[':
+		return ']'
+	}
+	return '}'
+}
@@ -0,0 +1,98 @@
+package jsonfilter
+
+import "github.com/tidwall/gjson"
+
+// PayloadPolicy selects what happens when a payload is not valid JSON. Operators read payloads with
+// gjson, which does not validate its input, so a truncated or garbled payload can still yield the
+// values that precede the damage and produce a partial match.
+type PayloadPolicy int
+
+const (
+	// PayloadEvaluate evaluates payloads without validating them. This is the behaviour of
+	// Evaluate.
+	PayloadEvaluate PayloadPolicy = iota
+	// PayloadNoMatch makes an invalid payload a non-match.
+	PayloadNoMatch
+	// PayloadReject makes an invalid payload an evaluation error, see EvaluationResult.Error.
+	PayloadReject
+)
+
+// ValidatingOperator validates the payload once before evaluating the operator tree below it, so
+// the cost of validation is paid once per payload rather than once per path lookup.
+type ValidatingOperator struct {
+	op     Operator
+	policy PayloadPolicy
+}
+
+const invalidPayloadMsg = "payload is not valid JSON"
+
+// WithPayloadValidation wraps the root of an operator tree so that payloads are validated according
+// to policy. PayloadEvaluate returns op unchanged, and wrapping a ValidatingOperator replaces its
+// policy.
+func WithPayloadValidation(op Operator, policy PayloadPolicy) Operator {
+	if wrapped, ok := op.(*ValidatingOperator); ok {
+		op = wrapped.op
+	}
+	if policy == PayloadEvaluate || op == nil {
+		return op
+	}
+	return &ValidatingOperator{op: op, policy: policy}
+}
+
+// Name returns the name of the wrapped operator.
+func (o *ValidatingOperator) Name() string {
+	return o.op.Name()
+}
+
+// Operator returns the wrapped operator.
+func (o *ValidatingOperator) Operator() Operator {
+	return o.op
+}
+
+// Policy returns how invalid payloads are handled.
+func (o *ValidatingOperator) Policy() PayloadPolicy {
+	return o.policy
+}
+
+// Evaluate validates json and evaluates the wrapped operator against it.
+func (o *ValidatingOperator) Evaluate(json []byte) EvaluationResult {
+	if !gjson.ValidBytes(json) {
+		return o.invalid(nil, BodyDocument, invalidPayloadMsg)
+	}
+	return o.op.Evaluate(json)
+}
+
+// EvaluateContext validates the body and every named document of ctx before evaluating the wrapped
+// operator.
+func (o *ValidatingOperator) EvaluateContext(ctx *Context) EvaluationResult {
+	if !gjson.ValidBytes(ctx.body) {
+		return o.invalid(ctx, BodyDocument, invalidPayloadMsg)
+	}
+	for i, doc := range ctx.docs {
+		if !gjson.ValidBytes(doc) {
+			return o.invalid(ctx, ctx.names[i], "document "+ctx.names[i]+" is not valid JSON")
+		}
+	}
+	return EvaluateContext(o.op, ctx)
+}
+
+// Validate checks the wrapped operator.
+func (o *ValidatingOperator) Validate() ValidationResult {
+	if o.op == nil {
+		return ErrorValidationResult("", "payload validation requires an operator")
+	}
+	return o.op.Validate()
+}
+
+func (o *ValidatingOperator) invalid(ctx *Context, document, msg string) EvaluationResult {
+	var res EvaluationResult
+	if o.policy == PayloadReject {
+		res = EvaluationErrorResult(o.op.Name(), msg)
+	} else {
+		res = ErrorResult(o.op.Name(), msg)
+	}
+	if ctx.Explaining() {
+		res.Cause = &Cause{Code: CauseInvalidPayload, Path: "@" + document}
+	}
+	return res
+}
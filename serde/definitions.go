@@ -0,0 +1,101 @@
+package serde
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+)
+
+const definitionPointerPrefix = "#/definitions/"
+
+// definitions holds the named sub-trees of one filter document. Each definition is compiled at most
+// once, so every reference to it shares the same operator instance.
+type definitions struct {
+	raw       map[string]interface{}
+	compiled  map[string]compiledDefinition
+	resolving []string
+}
+
+type compiledDefinition struct {
+	op         jsonfilter.Operator
+	complexity int
+}
+
+func newDefinitions(raw interface{}) (*definitions, error) {
+	defs, ok := normalizeMap(raw)
+	if !ok {
+		return nil, fmt.Errorf("definitions must be an object of named filters")
+	}
+	return &definitions{raw: defs, compiled: make(map[string]compiledDefinition, len(defs))}, nil
+}
+
+// names returns the definition names in sorted order so that errors are reported deterministically.
+func (d *definitions) names() []string {
+	names := make([]string, 0, len(d.raw))
+	for name := range d.raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseRef resolves a ref node. The reference costs the expanded complexity of its definition.
+func (p Parser) parseRef(name string, value interface{}) (jsonfilter.Operator, int, error) {
+	if name != "ref" && name != "$ref" {
+		return nil, 0, errUnsupportedOperator
+	}
+
+	target, _ := value.(string)
+	target = strings.TrimPrefix(target, definitionPointerPrefix)
+	if target == "" {
+		return nil, 0, fmt.Errorf("ref expects the name of a definition")
+	}
+	if p.defs == nil {
+		return nil, 0, fmt.Errorf("ref %q used without a definitions section", target)
+	}
+
+	def, err := p.resolveDefinition(target)
+	if err != nil {
+		return nil, 0, err
+	}
+	return def.op, def.complexity, nil
+}
+
+func (p Parser) resolveDefinition(name string) (compiledDefinition, error) {
+	d := p.defs
+	if def, ok := d.compiled[name]; ok {
+		return def, nil
+	}
+
+	for i, pending := range d.resolving {
+		if pending == name {
+			cycle := append(append([]string{}, d.resolving[i:]...), name)
+			return compiledDefinition{}, fmt.Errorf("definition cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	raw, ok := d.raw[name]
+	if !ok {
+		return compiledDefinition{}, fmt.Errorf("definition %q is not defined", name)
+	}
+	node, ok := normalizeMap(raw)
+	if !ok {
+		return compiledDefinition{}, fmt.Errorf("definition %q must be an operator object", name)
+	}
+
+	d.resolving = append(d.resolving, name)
+	op, complexity, err := p.parseOperator(node)
+	d.resolving = d.resolving[:len(d.resolving)-1]
+	if err != nil {
+		return compiledDefinition{}, fmt.Errorf("definition %q: %w", name, err)
+	}
+	if complexity > p.maxComplexity {
+		return compiledDefinition{}, fmt.Errorf("definition %q complexity %d exceeds limit %d", name, complexity, p.maxComplexity)
+	}
+
+	def := compiledDefinition{op: op, complexity: complexity}
+	d.compiled[name] = def
+	return def, nil
+}
@@ -1,4 +1,4 @@
-// Package serde converts YAML or JSON filter definitions into validated operator
-// trees while enforcing a configurable complexity limit to guard against overly
-// expensive filters.
+// Package serde converts YAML, JSON or text filter definitions into validated
+// operator trees while enforcing a configurable complexity limit to guard
+// against overly expensive filters, and prints operator trees back as text.
 package serde
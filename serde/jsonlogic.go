@@ -0,0 +1,584 @@
+package serde
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+)
+
+// ErrUnsupportedJSONLogic is wrapped by errors for JsonLogic operations FromJSONLogic cannot
+// represent as a filter, such as arithmetic, string building or array iteration.
+var ErrUnsupportedJSONLogic = errors.New("unsupported JsonLogic operation")
+
+var jsonLogicArithmetic = map[string]struct{}{
+	"+": {}, "-": {}, "*": {}, "/": {}, "%": {}, "min": {}, "max": {},
+}
+
+var jsonLogicOrdering = map[string]comparison.Type{
+	"<":  comparison.LessThan,
+	"<=": comparison.LessEqual,
+	">":  comparison.GreaterThan,
+	">=": comparison.GreaterEqual,
+}
+
+// jsonLogicFlipped gives the ordering that holds with the operands swapped.
+var jsonLogicFlipped = map[comparison.Type]comparison.Type{
+	comparison.LessThan:     comparison.GreaterThan,
+	comparison.LessEqual:    comparison.GreaterEqual,
+	comparison.GreaterThan:  comparison.LessThan,
+	comparison.GreaterEqual: comparison.LessEqual,
+}
+
+// jsonLogicTypes maps the kinds of jsonLogicKind onto the JSON types of the type operator.
+var jsonLogicTypes = map[string]string{
+	"bool":   comparison.TypeBoolean,
+	"string": comparison.TypeString,
+	"number": comparison.TypeNumber,
+}
+
+// FromJSONLogic compiles a JsonLogic rule such as
+//
+//	{"and": [{"==": [{"var": "a.b"}, 1]}, {"<": [0, {"var": "score"}, 10]}]}
+//
+// into an operator tree that matches when the rule result is truthy in JsonLogic terms: false, 0,
+// "", null, a missing value and the empty array are falsy. var paths are dot notation and map onto
+// gjson paths; "" addresses the whole payload. Supported operations are var (without a default),
+// ==, ===, !=, !==, <, <=, >, >= (including the three-argument between form), !, !!, and, or, if,
+// ?:, in, missing and missing_some. Comparisons take var references and literals as operands.
+// Arithmetic and every other operation fail with ErrUnsupportedJSONLogic.
+func (p Parser) FromJSONLogic(payload []byte) (jsonfilter.Operator, error) {
+	var rule interface{}
+	if err := decodeJSON(payload, &rule); err != nil {
+		return nil, err
+	}
+	return p.FromJSONLogicRule(rule)
+}
+
+// FromJSONLogicRule compiles an already unmarshaled JsonLogic rule, see FromJSONLogic.
+func (p Parser) FromJSONLogicRule(rule interface{}) (jsonfilter.Operator, error) {
+	def, err := translateJSONLogic(rule)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseRoot(def)
+}
+
+// translateJSONLogic converts a rule into a native definition matching when the rule is truthy.
+func translateJSONLogic(rule interface{}) (map[string]interface{}, error) {
+	node, ok := normalizeMap(rule)
+	if !ok {
+		return constantDef(jsonLogicTruthy(rule)), nil
+	}
+	op, args, err := jsonLogicOperation(node)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "var":
+		path, err := jsonLogicVar(args)
+		if err != nil {
+			return nil, err
+		}
+		return truthyDef(path), nil
+	case "!", "!!":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("JsonLogic %s expects one argument", op)
+		}
+		def, err := translateJSONLogic(args[0])
+		if err != nil || op == "!!" {
+			return def, err
+		}
+		return notDef(def), nil
+	case "and", "or":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("JsonLogic %s expects at least one argument", op)
+		}
+		children := make([]interface{}, len(args))
+		for i, arg := range args {
+			child, err := translateJSONLogic(arg)
+			if err != nil {
+				return nil, err
+			}
+			children[i] = child
+		}
+		typ := logic.And
+		if op == "or" {
+			typ = logic.Or
+		}
+		return map[string]interface{}{string(typ): children}, nil
+	case "if", "?:":
+		return translateJSONLogicIf(args)
+	case "==", "===", "!=", "!==":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("JsonLogic %s expects two arguments", op)
+		}
+		def, err := jsonLogicEquality(args[0], args[1], len(op) == 3)
+		if err != nil || op[0] == '=' {
+			return def, err
+		}
+		return notDef(def), nil
+	case "<", "<=", ">", ">=":
+		typ := jsonLogicOrdering[op]
+		if len(args) == 3 && (op == "<" || op == "<=") {
+			low, err := jsonLogicCompare(typ, args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			high, err := jsonLogicCompare(typ, args[1], args[2])
+			if err != nil {
+				return nil, err
+			}
+			return allDef([]interface{}{low, high}), nil
+		}
+		if len(args) != 2 {
+			return nil, fmt.Errorf("JsonLogic %s expects two arguments", op)
+		}
+		return jsonLogicCompare(typ, args[0], args[1])
+	case "in":
+		if len(args) != 2 {
+			return nil, errors.New("JsonLogic in expects two arguments")
+		}
+		return jsonLogicIn(args[0], args[1])
+	case "missing":
+		keys := args
+		if len(args) > 0 {
+			if list, ok := args[0].([]interface{}); ok {
+				keys = list
+			}
+		}
+		clauses := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			clause, err := missingDef(key)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, clause)
+		}
+		if len(clauses) == 0 {
+			return constantDef(false), nil
+		}
+		return anyDef(clauses...), nil
+	case "missing_some":
+		return translateJSONLogicMissingSome(args)
+	default:
+		if _, ok := jsonLogicArithmetic[op]; ok {
+			return nil, fmt.Errorf("%w %q: arithmetic cannot be represented as a filter", ErrUnsupportedJSONLogic, op)
+		}
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedJSONLogic, op)
+	}
+}
+
+// jsonLogicOperation splits a rule object into its operation and argument list. A single
+// non-array argument is shorthand for a one-element list.
+func jsonLogicOperation(node map[string]interface{}) (string, []interface{}, error) {
+	if len(node) != 1 {
+		return "", nil, fmt.Errorf("JsonLogic rule must contain exactly one operation, got %v", mapKeys(node))
+	}
+	for op, raw := range node {
+		if args, ok := raw.([]interface{}); ok {
+			return op, args, nil
+		}
+		return op, []interface{}{raw}, nil
+	}
+	return "", nil, errors.New("could not parse JsonLogic rule")
+}
+
+// jsonLogicVar returns the gjson path addressed by the arguments of var.
+func jsonLogicVar(args []interface{}) (string, error) {
+	if len(args) > 1 {
+		return "", fmt.Errorf("%w \"var\" with a default value", ErrUnsupportedJSONLogic)
+	}
+	if len(args) == 0 || args[0] == nil {
+		return "@this", nil
+	}
+	var name string
+	switch typed := args[0].(type) {
+	case string:
+		name = typed
+	case json.Number:
+		name = typed.String()
+	case float64:
+		name = strconv.FormatFloat(typed, 'f', -1, 64)
+	case int:
+		name = strconv.Itoa(typed)
+	default:
+		return "", fmt.Errorf("%w \"var\" with a computed name", ErrUnsupportedJSONLogic)
+	}
+	if name == "" {
+		return "@this", nil
+	}
+	return dottedPath(name), nil
+}
+
+// jsonLogicOperand is a comparison argument: a var reference or a literal.
+type jsonLogicOperand struct {
+	path  string
+	value interface{}
+}
+
+func (o jsonLogicOperand) isPath() bool {
+	return o.path != ""
+}
+
+func jsonLogicOperandOf(raw interface{}) (jsonLogicOperand, error) {
+	node, ok := normalizeMap(raw)
+	if !ok {
+		return jsonLogicOperand{value: raw}, nil
+	}
+	op, args, err := jsonLogicOperation(node)
+	if err != nil {
+		return jsonLogicOperand{}, err
+	}
+	if op != "var" {
+		return jsonLogicOperand{}, fmt.Errorf("%w %q as a comparison operand", ErrUnsupportedJSONLogic, op)
+	}
+	path, err := jsonLogicVar(args)
+	if err != nil {
+		return jsonLogicOperand{}, err
+	}
+	return jsonLogicOperand{path: path}, nil
+}
+
+func jsonLogicEquality(rawLeft, rawRight interface{}, strict bool) (map[string]interface{}, error) {
+	left, err := jsonLogicOperandOf(rawLeft)
+	if err != nil {
+		return nil, err
+	}
+	right, err := jsonLogicOperandOf(rawRight)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case left.isPath() && right.isPath():
+		return referenceDef(comparison.Equal, left.path, right.path), nil
+	case left.isPath():
+		return jsonLogicEqualValue(left.path, right.value, strict), nil
+	case right.isPath():
+		return jsonLogicEqualValue(right.path, left.value, strict), nil
+	default:
+		return constantDef(jsonLogicEqual(left.value, right.value, strict)), nil
+	}
+}
+
+// jsonLogicEqualValue compares a var with a literal like JavaScript's === or ==. A missing var reads
+// as null. eq on its own reads other JSON types leniently, so every clause is guarded by the type it
+// applies to: === only accepts the literal's own type, while == also accepts the numbers, numeric
+// strings and booleans the literal converts to.
+func jsonLogicEqualValue(path string, value interface{}, strict bool) map[string]interface{} {
+	kind := jsonLogicKind(value)
+	switch {
+	case kind == "null":
+		return anyDef(comparisonDef(comparison.Equal, path, nil), comparisonDef(comparison.Exists, path, false))
+	case kind == "other":
+		return constantDef(false)
+	case strict:
+		return typedDef(path, jsonLogicTypes[kind], comparisonDef(comparison.Equal, path, value))
+	}
+
+	var clauses []interface{}
+	n := jsonLogicNumber(value)
+	if kind == "string" {
+		same := typedDef(path, comparison.TypeString, comparisonDef(comparison.Equal, path, value))
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return same
+		}
+		clauses = append(clauses, same)
+	}
+	number := value
+	if kind != "number" {
+		number = json.Number(strconv.FormatFloat(n, 'g', -1, 64))
+	}
+	clauses = append(clauses, typedDef(path, comparison.TypeNumber, comparisonDef(comparison.Equal, path, number)))
+	if kind != "string" {
+		clauses = append(clauses, typedDef(path, comparison.TypeString, comparisonDef(comparison.Equal, path, number)))
+		if n == 0 {
+			clauses = append(clauses, typedDef(path, comparison.TypeString, comparisonDef(comparison.Equal, path, "")))
+		}
+	}
+	if n == 0 || n == 1 {
+		clauses = append(clauses, typedDef(path, comparison.TypeBoolean, comparisonDef(comparison.Equal, path, n == 1)))
+	}
+	return anyDef(clauses...)
+}
+
+func jsonLogicCompare(typ comparison.Type, rawLeft, rawRight interface{}) (map[string]interface{}, error) {
+	left, err := jsonLogicOperandOf(rawLeft)
+	if err != nil {
+		return nil, err
+	}
+	right, err := jsonLogicOperandOf(rawRight)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case left.isPath() && right.isPath():
+		return referenceDef(typ, left.path, right.path), nil
+	case left.isPath():
+		return comparisonDef(typ, left.path, right.value), nil
+	case right.isPath():
+		return comparisonDef(jsonLogicFlipped[typ], right.path, left.value), nil
+	default:
+		return constantDef(jsonLogicOrder(typ, left.value, right.value)), nil
+	}
+}
+
+// jsonLogicIn supports a var looked up in a literal list and a literal searched in a var holding a
+// string or an array.
+func jsonLogicIn(rawNeedle, rawHaystack interface{}) (map[string]interface{}, error) {
+	needle, err := jsonLogicOperandOf(rawNeedle)
+	if err != nil {
+		return nil, err
+	}
+	haystack, err := jsonLogicOperandOf(rawHaystack)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case needle.isPath() && !haystack.isPath():
+		values, ok := haystack.value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w \"in\" with a var needle and a non-array literal", ErrUnsupportedJSONLogic)
+		}
+		if len(values) == 0 {
+			return constantDef(false), nil
+		}
+		return comparisonDef(comparison.In, needle.path, values), nil
+	case !needle.isPath() && haystack.isPath():
+		return comparisonDef(comparison.Contains, haystack.path, needle.value), nil
+	case !needle.isPath():
+		switch typed := haystack.value.(type) {
+		case string:
+			sub, ok := needle.value.(string)
+			return constantDef(ok && strings.Contains(typed, sub)), nil
+		case []interface{}:
+			for _, item := range typed {
+				if jsonLogicEqual(needle.value, item, true) {
+					return constantDef(true), nil
+				}
+			}
+		}
+		return constantDef(false), nil
+	default:
+		return nil, fmt.Errorf("%w \"in\" between two vars", ErrUnsupportedJSONLogic)
+	}
+}
+
+// translateJSONLogicIf chains condition/branch pairs; an odd trailing argument is the else branch
+// and without one the result is null, which is falsy.
+func translateJSONLogicIf(args []interface{}) (map[string]interface{}, error) {
+	switch len(args) {
+	case 0:
+		return constantDef(false), nil
+	case 1:
+		return translateJSONLogic(args[0])
+	}
+	cond, err := translateJSONLogic(args[0])
+	if err != nil {
+		return nil, err
+	}
+	then, err := translateJSONLogic(args[1])
+	if err != nil {
+		return nil, err
+	}
+	otherwise, err := translateJSONLogicIf(args[2:])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{string(logic.If): map[string]interface{}{"if": cond, "then": then, "else": otherwise}}, nil
+}
+
+// translateJSONLogicMissingSome is truthy when fewer than the required number of keys are present.
+func translateJSONLogicMissingSome(args []interface{}) (map[string]interface{}, error) {
+	if len(args) != 2 {
+		return nil, errors.New("JsonLogic missing_some expects a count and a list of keys")
+	}
+	need, ok := toCount(args[0])
+	if !ok {
+		return nil, errors.New("JsonLogic missing_some expects a non-negative integer count")
+	}
+	keys, ok := args[1].([]interface{})
+	if !ok {
+		return nil, errors.New("JsonLogic missing_some expects a list of keys")
+	}
+	switch {
+	case need == 0:
+		return constantDef(false), nil
+	case need > len(keys):
+		return constantDef(true), nil
+	}
+	present := make([]interface{}, len(keys))
+	for i, key := range keys {
+		clause, err := missingDef(key)
+		if err != nil {
+			return nil, err
+		}
+		present[i] = notDef(clause)
+	}
+	return map[string]interface{}{string(logic.AtMost): map[string]interface{}{"count": need - 1, "of": present}}, nil
+}
+
+// missingDef matches when key is absent, null or the empty string, as JsonLogic's missing does.
+func missingDef(key interface{}) (map[string]interface{}, error) {
+	path, err := jsonLogicVar([]interface{}{key})
+	if err != nil {
+		return nil, err
+	}
+	return anyDef(
+		comparisonDef(comparison.Exists, path, false),
+		comparisonDef(comparison.JSONType, path, comparison.TypeNull),
+		typedDef(path, comparison.TypeString, comparisonDef(comparison.Size, path, 0)),
+	), nil
+}
+
+// truthyDef matches when the value at path is truthy in JsonLogic terms. Each falsy value is
+// guarded by its JSON type, since eq on its own reads non-matching types leniently.
+func truthyDef(path string) map[string]interface{} {
+	return map[string]interface{}{string(logic.Nor): []interface{}{
+		comparisonDef(comparison.Exists, path, false),
+		comparisonDef(comparison.JSONType, path, comparison.TypeNull),
+		typedDef(path, comparison.TypeBoolean, comparisonDef(comparison.Equal, path, false)),
+		typedDef(path, comparison.TypeNumber, comparisonDef(comparison.Equal, path, 0)),
+		typedDef(path, []interface{}{comparison.TypeString, comparison.TypeArray}, comparisonDef(comparison.Size, path, 0)),
+	}}
+}
+
+// referenceDef compares the value at field with the value at ref, for comparisons between two vars.
+func referenceDef(typ comparison.Type, field, ref string) map[string]interface{} {
+	return map[string]interface{}{string(typ): map[string]interface{}{"field": field, "valueFrom": ref}}
+}
+
+// jsonLogicTruthy reports whether a literal is truthy; unlike JavaScript the empty array is falsy.
+func jsonLogicTruthy(value interface{}) bool {
+	switch typed := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return typed
+	case string:
+		return typed != ""
+	case []interface{}:
+		return len(typed) > 0
+	case json.Number, float64, int:
+		n := jsonLogicNumber(typed)
+		return n != 0 && !math.IsNaN(n)
+	default:
+		return true
+	}
+}
+
+// jsonLogicEqual folds == (loose) and === (strict) between two literals the way JavaScript does for
+// primitive values. Arrays and objects are never equal, as they compare by reference.
+func jsonLogicEqual(a, b interface{}, strict bool) bool {
+	kindA, kindB := jsonLogicKind(a), jsonLogicKind(b)
+	if kindA == kindB {
+		switch kindA {
+		case "null":
+			return true
+		case "bool":
+			return a.(bool) == b.(bool)
+		case "string":
+			return a.(string) == b.(string)
+		case "number":
+			return jsonLogicNumber(a) == jsonLogicNumber(b)
+		default:
+			return false
+		}
+	}
+	if strict || kindA == "null" || kindB == "null" || kindA == "other" || kindB == "other" {
+		return false
+	}
+	return jsonLogicNumber(a) == jsonLogicNumber(b)
+}
+
+// jsonLogicOrder folds an ordering between two literals: strings compare lexically, anything else
+// numerically.
+func jsonLogicOrder(typ comparison.Type, a, b interface{}) bool {
+	if sa, ok := a.(string); ok {
+		if sb, ok := b.(string); ok {
+			return orderHolds(typ, strings.Compare(sa, sb))
+		}
+	}
+	na, nb := jsonLogicNumber(a), jsonLogicNumber(b)
+	if math.IsNaN(na) || math.IsNaN(nb) {
+		return false
+	}
+	switch {
+	case na < nb:
+		return orderHolds(typ, -1)
+	case na > nb:
+		return orderHolds(typ, 1)
+	default:
+		return orderHolds(typ, 0)
+	}
+}
+
+func orderHolds(typ comparison.Type, cmp int) bool {
+	switch typ {
+	case comparison.LessThan:
+		return cmp < 0
+	case comparison.LessEqual:
+		return cmp <= 0
+	case comparison.GreaterThan:
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func jsonLogicKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case json.Number, float64, int:
+		return "number"
+	default:
+		return "other"
+	}
+}
+
+// jsonLogicNumber converts a primitive to a number like JavaScript's Number().
+func jsonLogicNumber(value interface{}) float64 {
+	switch typed := value.(type) {
+	case nil:
+		return 0
+	case bool:
+		if typed {
+			return 1
+		}
+		return 0
+	case json.Number:
+		n, err := typed.Float64()
+		if err != nil {
+			return math.NaN()
+		}
+		return n
+	case float64:
+		return typed
+	case int:
+		return float64(typed)
+	case string:
+		trimmed := strings.TrimSpace(typed)
+		if trimmed == "" {
+			return 0
+		}
+		n, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+			return math.NaN()
+		}
+		return n
+	default:
+		return math.NaN()
+	}
+}
@@ -0,0 +1,143 @@
+package serde
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+// unsupportedJSONLogicVectors lists the boolean-valued rules of the vector file that FromJSONLogic
+// is expected to reject, so that support cannot shrink unnoticed.
+var unsupportedJSONLogicVectors = map[string]bool{
+	`{"all":[[1,2,3], {">":[{"var":""}, 0]}]}`:     true,
+	`{"some":[[-1,0,1], {">":[{"var":""}, 0]}]}`:   true,
+	`{"none":[[-3,-2,-1], {">":[{"var":""}, 0]}]}`: true,
+}
+
+// TestFromJSONLogicVectors runs the vectors whose expected result is a boolean, the only results a
+// filter can reproduce exactly. Every such rule must either translate and evaluate to that boolean
+// or be listed in unsupportedJSONLogicVectors.
+func TestFromJSONLogicVectors(t *testing.T) {
+	raw, err := os.ReadFile("testdata/jsonlogic/tests.json")
+	if err != nil {
+		t.Fatalf("read vectors: %v", err)
+	}
+	var entries []json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		t.Fatalf("decode vectors: %v", err)
+	}
+
+	checked, rejected := 0, 0
+	for _, entry := range entries {
+		var vector []json.RawMessage
+		if err := json.Unmarshal(entry, &vector); err != nil {
+			continue // section comment
+		}
+		if len(vector) != 3 {
+			t.Fatalf("malformed vector %s", entry)
+		}
+		var expected bool
+		if err := json.Unmarshal(vector[2], &expected); err != nil {
+			continue // not boolean-valued
+		}
+
+		rule := string(vector[0])
+		op, err := DefaultParser().FromJSONLogic(vector[0])
+		if unsupportedJSONLogicVectors[rule] {
+			if !errors.Is(err, ErrUnsupportedJSONLogic) {
+				t.Fatalf("%s: expected unsupported operation error, got %v", rule, err)
+			}
+			rejected++
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", rule, err)
+		}
+		if res := op.Evaluate(vector[1]); res.Match != expected {
+			t.Fatalf("%s with %s: expected %v, got %#v", rule, vector[1], expected, res)
+		}
+		checked++
+	}
+	if rejected != len(unsupportedJSONLogicVectors) {
+		t.Fatalf("found %d of %d unsupported vectors", rejected, len(unsupportedJSONLogicVectors))
+	}
+	if checked != 133 {
+		t.Fatalf("expected 133 boolean-valued vectors to be checked, got %d", checked)
+	}
+}
+
+func TestFromJSONLogicRules(t *testing.T) {
+	cases := []struct {
+		rule    string
+		matches []string
+		misses  []string
+	}{
+		{
+			rule:    `{"and": [{"==": [{"var": "a.b"}, 1]}, {"<": [0, {"var": "score"}, 10]}]}`,
+			matches: []string{`{"a":{"b":1},"score":5}`},
+			misses:  []string{`{"a":{"b":2},"score":5}`, `{"a":{"b":1},"score":10}`, `{"a":{"b":1}}`},
+		},
+		{
+			rule:    `{"!=": [{"var": "status"}, "closed"]}`,
+			matches: []string{`{"status":"open"}`, `{}`},
+			misses:  []string{`{"status":"closed"}`},
+		},
+		{
+			rule:    `{"==": [{"var": "owner"}, null]}`,
+			matches: []string{`{"owner":null}`, `{}`},
+			misses:  []string{`{"owner":"bob"}`},
+		},
+		{
+			rule:    `{">=": [{"var": "amount"}, {"var": "limit"}]}`,
+			matches: []string{`{"amount":10,"limit":10}`},
+			misses:  []string{`{"amount":9,"limit":10}`},
+		},
+		{
+			rule:    `{"in": ["vip", {"var": "tags"}]}`,
+			matches: []string{`{"tags":["new","vip"]}`, `{"tags":"vip-gold"}`},
+			misses:  []string{`{"tags":["new"]}`},
+		},
+		{
+			rule:    `{"if": [{"var": "financing"}, {"!": {"missing": "apr"}}, true]}`,
+			matches: []string{`{"financing":true,"apr":3}`, `{"financing":false}`},
+			misses:  []string{`{"financing":true}`, `{"financing":true,"apr":""}`},
+		},
+	}
+	for _, tc := range cases {
+		op, err := DefaultParser().FromJSONLogic([]byte(tc.rule))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.rule, err)
+		}
+		for _, payload := range tc.matches {
+			if res := op.Evaluate([]byte(payload)); !res.Match {
+				t.Fatalf("%s: expected %s to match: %#v", tc.rule, payload, res)
+			}
+		}
+		for _, payload := range tc.misses {
+			if res := op.Evaluate([]byte(payload)); res.Match {
+				t.Fatalf("%s: expected %s not to match", tc.rule, payload)
+			}
+		}
+	}
+}
+
+func TestFromJSONLogicErrors(t *testing.T) {
+	for _, rule := range []string{
+		`{">": [{"+": [{"var": "a"}, 1]}, 2]}`,
+		`{"==": [{"var": ["a", 0]}, 1]}`,
+		`{"some": [{"var": "items"}, {"var": "ok"}]}`,
+		`{"in": [{"var": "a"}, {"var": "b"}]}`,
+	} {
+		_, err := DefaultParser().FromJSONLogic([]byte(rule))
+		if !errors.Is(err, ErrUnsupportedJSONLogic) {
+			t.Fatalf("%s: expected unsupported operation error, got %v", rule, err)
+		}
+	}
+	if _, err := DefaultParser().FromJSONLogic([]byte(`{"==": [1, 2]} garbage`)); err == nil {
+		t.Fatalf("expected trailing data after a rule to be rejected")
+	}
+	if _, err := DefaultParser().FromJSONLogic([]byte(`{"==": [1, 2], "!=": [1, 2]}`)); err == nil {
+		t.Fatalf("expected rule with two operations to be rejected")
+	}
+}
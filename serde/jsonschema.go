@@ -0,0 +1,13 @@
+package serde
+
+import _ "embed"
+
+//go:embed filter.schema.json
+var filterSchema []byte
+
+// JSONSchema returns a JSON Schema (draft-07) describing the filter document format accepted by
+// FromJSON and FromYAML. Editors can use it for validation and autocompletion, for example by
+// adding "$schema" to a filter document, which the parser ignores.
+func JSONSchema() []byte {
+	return append([]byte(nil), filterSchema...)
+}
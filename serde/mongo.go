@@ -0,0 +1,390 @@
+package serde
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/nested"
+	"github.com/tidwall/gjson"
+)
+
+// ErrUnsupportedMongoOperator is wrapped by errors for MongoDB query operators FromMongo cannot
+// translate, such as $where, $expr or $mod.
+var ErrUnsupportedMongoOperator = errors.New("unsupported MongoDB operator")
+
+// mongoTypeAliases maps $type names and numeric BSON type codes onto the JSON types understood by the
+// type comparison operator. BSON types without a JSON counterpart are not supported.
+var mongoTypeAliases = map[string]string{
+	"double":  comparison.TypeNumber,
+	"decimal": comparison.TypeNumber,
+	"number":  comparison.TypeNumber,
+	"int":     comparison.TypeInteger,
+	"long":    comparison.TypeInteger,
+	"string":  comparison.TypeString,
+	"bool":    comparison.TypeBoolean,
+	"object":  comparison.TypeObject,
+	"array":   comparison.TypeArray,
+	"null":    comparison.TypeNull,
+	"1":       comparison.TypeNumber,
+	"2":       comparison.TypeString,
+	"3":       comparison.TypeObject,
+	"4":       comparison.TypeArray,
+	"8":       comparison.TypeBoolean,
+	"10":      comparison.TypeNull,
+	"16":      comparison.TypeInteger,
+	"18":      comparison.TypeInteger,
+	"19":      comparison.TypeNumber,
+}
+
+// FromMongo compiles a MongoDB query document such as
+//
+//	{"status": "A", "qty": {"$lt": 30}, "$or": [{"tags": {"$all": ["red"]}}, {"size.h": {"$gte": 10}}]}
+//
+// into the equivalent native operator tree. The supported subset is $eq, $ne, $gt, $gte, $lt, $lte,
+// $in, $nin, $exists, $type, $regex (with the i, m and s options), $and, $or, $nor, $not, $elemMatch,
+// $size and $all; $comment is ignored. Any other operator fails with ErrUnsupportedMongoOperator.
+//
+// As in MongoDB, equality and $in match an array field when one of its elements matches, a null
+// value also matches a missing field, and $ne, $nin and $not match documents lacking the field.
+// Ordering operators compare the field value itself rather than array elements. Numbers are decoded
+// as json.Number, like FromJSON.
+func (p Parser) FromMongo(payload []byte) (jsonfilter.Operator, error) {
+	var query map[string]interface{}
+	if err := decodeJSON(payload, &query); err != nil {
+		return nil, err
+	}
+	return p.FromMongoMap(query)
+}
+
+// FromMongoMap compiles an already unmarshaled MongoDB query document, see FromMongo.
+func (p Parser) FromMongoMap(query map[string]interface{}) (jsonfilter.Operator, error) {
+	if query == nil {
+		return nil, errors.New("filter definition cannot be empty")
+	}
+	def, err := translateMongoQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseRoot(def)
+}
+
+// translateMongoQuery converts a query document into a native operator definition. Clauses are
+// combined with and in key order so that the resulting tree does not depend on map iteration.
+func translateMongoQuery(query map[string]interface{}) (map[string]interface{}, error) {
+	keys := mapKeys(query)
+	sort.Strings(keys)
+
+	clauses := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		raw := query[key]
+		if !strings.HasPrefix(key, "$") {
+			clause, err := translateMongoField(dottedPath(key), raw)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, clause)
+			continue
+		}
+
+		switch key {
+		case "$and", "$or", "$nor":
+			clause, err := translateMongoLogic(key, raw)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, clause)
+		case "$comment":
+		default:
+			return nil, fmt.Errorf("%w %s", ErrUnsupportedMongoOperator, key)
+		}
+	}
+	if len(clauses) == 0 {
+		// The empty query matches every document.
+		return comparisonDef(comparison.Exists, "@this", true), nil
+	}
+	return allDef(clauses), nil
+}
+
+func translateMongoLogic(key string, raw interface{}) (map[string]interface{}, error) {
+	items, ok := raw.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("%s expects a non-empty array of query documents", key)
+	}
+	children := make([]interface{}, len(items))
+	for i, item := range items {
+		sub, ok := normalizeMap(item)
+		if !ok {
+			return nil, fmt.Errorf("%s item %d must be a query document", key, i)
+		}
+		child, err := translateMongoQuery(sub)
+		if err != nil {
+			return nil, err
+		}
+		children[i] = child
+	}
+	typ := map[string]logic.Type{"$and": logic.And, "$or": logic.Or, "$nor": logic.Nor}[key]
+	return map[string]interface{}{string(typ): children}, nil
+}
+
+// translateMongoField converts the condition on a single field: either an operator document whose
+// keys all start with "$", or a literal compared for equality.
+func translateMongoField(field string, raw interface{}) (map[string]interface{}, error) {
+	ops, ok := normalizeMap(raw)
+	if !ok || !isMongoOperatorDoc(ops) {
+		return mongoEqual(field, raw), nil
+	}
+	return translateMongoOperators(field, ops)
+}
+
+func isMongoOperatorDoc(doc map[string]interface{}) bool {
+	if len(doc) == 0 {
+		return false
+	}
+	for key := range doc {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+func translateMongoOperators(field string, ops map[string]interface{}) (map[string]interface{}, error) {
+	keys := mapKeys(ops)
+	sort.Strings(keys)
+
+	clauses := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		raw := ops[key]
+		var clause map[string]interface{}
+		var err error
+		switch key {
+		case "$eq":
+			clause = mongoEqual(field, raw)
+		case "$ne":
+			clause = notDef(mongoEqual(field, raw))
+		case "$gt", "$gte", "$lt", "$lte":
+			typ := map[string]comparison.Type{
+				"$gt": comparison.GreaterThan, "$gte": comparison.GreaterEqual,
+				"$lt": comparison.LessThan, "$lte": comparison.LessEqual,
+			}[key]
+			clause = comparisonDef(typ, field, raw)
+		case "$in":
+			clause, err = mongoIn(field, raw)
+		case "$nin":
+			clause, err = mongoIn(field, raw)
+			clause = notDef(clause)
+		case "$exists":
+			clause = comparisonDef(comparison.Exists, field, mongoTruthy(raw))
+		case "$type":
+			clause, err = mongoType(field, raw)
+		case "$regex":
+			clause, err = mongoRegex(field, raw, ops["$options"])
+		case "$options":
+			if _, ok := ops["$regex"]; !ok {
+				return nil, errors.New("$options requires $regex")
+			}
+			continue
+		case "$not":
+			sub, ok := normalizeMap(raw)
+			if !ok || !isMongoOperatorDoc(sub) {
+				return nil, errors.New("$not expects an operator document")
+			}
+			clause, err = translateMongoOperators(field, sub)
+			clause = notDef(clause)
+		case "$elemMatch":
+			clause, err = mongoElemMatch(field, raw)
+		case "$size":
+			clause = typedDef(field, comparison.TypeArray, comparisonDef(comparison.Size, field, raw))
+		case "$all":
+			clause, err = mongoAllOf(field, raw)
+		default:
+			return nil, fmt.Errorf("%w %s", ErrUnsupportedMongoOperator, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+	return allDef(clauses), nil
+}
+
+// mongoEqual matches the field value, or one of its elements when the field is an array. Scalars
+// use in with a single value, which already has those semantics; arrays and documents are compared
+// as a whole.
+func mongoEqual(field string, value interface{}) map[string]interface{} {
+	switch value.(type) {
+	case []interface{}, map[string]interface{}, map[interface{}]interface{}:
+		return comparisonDef(comparison.Equal, field, value)
+	}
+	clause := comparisonDef(comparison.In, field, []interface{}{value})
+	if value == nil {
+		return anyDef(clause, comparisonDef(comparison.Exists, field, false))
+	}
+	return clause
+}
+
+func mongoIn(field string, raw interface{}) (map[string]interface{}, error) {
+	values, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.New("$in and $nin expect an array")
+	}
+	if len(values) == 0 {
+		// Nothing is in the empty list, so $in never matches and $nin always does.
+		return constantDef(false), nil
+	}
+	clause := comparisonDef(comparison.In, field, values)
+	for _, value := range values {
+		if value == nil {
+			return anyDef(clause, comparisonDef(comparison.Exists, field, false)), nil
+		}
+	}
+	return clause, nil
+}
+
+func mongoType(field string, raw interface{}) (map[string]interface{}, error) {
+	names, ok := raw.([]interface{})
+	if !ok {
+		names = []interface{}{raw}
+	}
+	types := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		typ, ok := mongoTypeAliases[fmt.Sprint(name)]
+		if !ok {
+			return nil, fmt.Errorf("$type %v is not supported", name)
+		}
+		types = append(types, typ)
+	}
+	return comparisonDef(comparison.JSONType, field, types), nil
+}
+
+func mongoRegex(field string, raw, rawOptions interface{}) (map[string]interface{}, error) {
+	pattern, ok := raw.(string)
+	if !ok {
+		return nil, errors.New("$regex expects a string pattern")
+	}
+	if rawOptions != nil {
+		options, ok := rawOptions.(string)
+		if !ok {
+			return nil, errors.New("$options expects a string")
+		}
+		for _, option := range options {
+			if !strings.ContainsRune("ims", option) {
+				return nil, fmt.Errorf("%w $options %q", ErrUnsupportedMongoOperator, option)
+			}
+		}
+		if options != "" {
+			pattern = "(?" + options + ")" + pattern
+		}
+	}
+	return comparisonDef(comparison.Regex, field, pattern), nil
+}
+
+// mongoElemMatch evaluates the inner document against each element. A document made only of
+// operators applies to the element itself, as in {"scores": {"$elemMatch": {"$gte": 80}}}.
+func mongoElemMatch(field string, raw interface{}) (map[string]interface{}, error) {
+	sub, ok := normalizeMap(raw)
+	if !ok {
+		return nil, errors.New("$elemMatch expects a document")
+	}
+	var filter map[string]interface{}
+	var err error
+	if isMongoOperatorDoc(sub) && !hasMongoLogic(sub) {
+		filter, err = translateMongoOperators("@this", sub)
+	} else {
+		filter, err = translateMongoQuery(sub)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{nested.ElemMatchName: map[string]interface{}{"field": field, "filter": filter}}, nil
+}
+
+func hasMongoLogic(doc map[string]interface{}) bool {
+	for _, key := range []string{"$and", "$or", "$nor"} {
+		if _, ok := doc[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func mongoAllOf(field string, raw interface{}) (map[string]interface{}, error) {
+	values, ok := raw.([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, errors.New("$all expects a non-empty array")
+	}
+	clauses := make([]interface{}, len(values))
+	for i, value := range values {
+		clauses[i] = mongoEqual(field, value)
+	}
+	return allDef(clauses), nil
+}
+
+func comparisonDef(typ comparison.Type, field string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{string(typ): map[string]interface{}{"field": field, "value": value}}
+}
+
+// typedDef guards clause with the JSON type of the value at path.
+func typedDef(path string, types interface{}, clause map[string]interface{}) map[string]interface{} {
+	return allDef([]interface{}{comparisonDef(comparison.JSONType, path, types), clause})
+}
+
+// constantDef matches every document when match is set and none otherwise.
+func constantDef(match bool) map[string]interface{} {
+	always := comparisonDef(comparison.Exists, "@this", true)
+	if match {
+		return always
+	}
+	return notDef(always)
+}
+
+func notDef(clause map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{string(logic.Nor): []interface{}{clause}}
+}
+
+func anyDef(clauses ...interface{}) map[string]interface{} {
+	return map[string]interface{}{string(logic.Or): clauses}
+}
+
+// allDef joins clauses with and, leaving a single clause unwrapped.
+func allDef(clauses []interface{}) map[string]interface{} {
+	if len(clauses) == 1 {
+		return clauses[0].(map[string]interface{})
+	}
+	return map[string]interface{}{string(logic.And): clauses}
+}
+
+// dottedPath escapes gjson metacharacters in each dot-separated component of a field path written
+// in dot notation, as used by MongoDB and JsonLogic.
+func dottedPath(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = gjson.Escape(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// mongoTruthy follows MongoDB's reading of $exists: false, 0 and null are false, anything else true.
+func mongoTruthy(value interface{}) bool {
+	switch typed := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return typed
+	case json.Number:
+		f, err := typed.Float64()
+		return err != nil || f != 0
+	case float64:
+		return typed != 0
+	case int:
+		return typed != 0
+	default:
+		return true
+	}
+}
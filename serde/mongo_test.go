@@ -0,0 +1,153 @@
+package serde
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFromMongoOperators(t *testing.T) {
+	cases := []struct {
+		query   string
+		matches []string
+		misses  []string
+	}{
+		{
+			query:   `{"status": "A", "qty": {"$gte": 10, "$lt": 30}}`,
+			matches: []string{`{"status":"A","qty":10}`, `{"status":"A","qty":29.5}`},
+			misses:  []string{`{"status":"B","qty":20}`, `{"status":"A","qty":30}`, `{"status":"A"}`},
+		},
+		{
+			query:   `{"tags": "red"}`,
+			matches: []string{`{"tags":"red"}`, `{"tags":["blue","red"]}`},
+			misses:  []string{`{"tags":["blue"]}`, `{"tags":"reddish"}`, `{}`},
+		},
+		{
+			query:   `{"tags": {"$ne": "red"}}`,
+			matches: []string{`{"tags":["blue"]}`, `{}`},
+			misses:  []string{`{"tags":["red"]}`, `{"tags":"red"}`},
+		},
+		{
+			query:  `{"size": {"$in": []}}`,
+			misses: []string{`{"size":"S"}`, `{"size":[]}`, `{}`},
+		},
+		{
+			query:   `{"size": {"$nin": []}}`,
+			matches: []string{`{"size":"S"}`, `{"size":[]}`, `{}`},
+		},
+		{
+			query:   `{"owner": null}`,
+			matches: []string{`{"owner":null}`, `{}`},
+			misses:  []string{`{"owner":"bob"}`},
+		},
+		{
+			query:   `{"size": {"$in": ["S", "M"]}, "color": {"$nin": ["red"]}}`,
+			matches: []string{`{"size":"M","color":"blue"}`, `{"size":["XL","S"]}`},
+			misses:  []string{`{"size":"L"}`, `{"size":"S","color":"red"}`},
+		},
+		{
+			query:   `{"a.b": {"$exists": true}, "c": {"$exists": 0}, "d": {"$type": ["string", "int"]}}`,
+			matches: []string{`{"a":{"b":null},"d":"x"}`, `{"a":{"b":1},"d":4}`},
+			misses:  []string{`{"a":{},"d":"x"}`, `{"a":{"b":1},"c":1,"d":"x"}`, `{"a":{"b":1},"d":4.5}`},
+		},
+		{
+			query:   `{"name": {"$regex": "^bo", "$options": "i"}, "nick": {"$not": {"$regex": "x"}}}`,
+			matches: []string{`{"name":"Bob"}`, `{"name":"bono","nick":"b"}`},
+			misses:  []string{`{"name":"alice"}`, `{"name":"bob","nick":"rex"}`},
+		},
+		{
+			query:   `{"$or": [{"qty": {"$lt": 20}}, {"price": 10}], "$nor": [{"sale": true}], "$comment": "promo"}`,
+			matches: []string{`{"qty":5}`, `{"qty":50,"price":10,"sale":false}`},
+			misses:  []string{`{"qty":50,"price":9}`, `{"qty":5,"sale":true}`},
+		},
+		{
+			query:   `{"results": {"$elemMatch": {"product": "xyz", "score": {"$gte": 8}}}, "scores": {"$elemMatch": {"$gte": 80, "$lt": 85}}}`,
+			matches: []string{`{"results":[{"product":"abc","score":10},{"product":"xyz","score":9}],"scores":[70,82]}`},
+			misses: []string{
+				`{"results":[{"product":"abc","score":10},{"product":"xyz","score":5}],"scores":[82]}`,
+				`{"results":[{"product":"xyz","score":9}],"scores":[70,90]}`,
+				`{"results":{"product":"xyz","score":9},"scores":[82]}`,
+			},
+		},
+		{
+			query:   `{"tags": {"$all": ["red", "blank"], "$size": 3}}`,
+			matches: []string{`{"tags":["blank","red","x"]}`},
+			misses:  []string{`{"tags":["blank","red"]}`, `{"tags":["red","x","y"]}`},
+		},
+		{
+			query:   `{"tags": {"$size": 3}}`,
+			matches: []string{`{"tags":[1,2,3]}`},
+			misses:  []string{`{"tags":"abc"}`, `{"tags":{"a":1,"b":2,"c":3}}`, `{"tags":[1,2]}`, `{}`},
+		},
+		{
+			query:   `{"odd*key": 1}`,
+			matches: []string{`{"odd*key":1}`},
+			misses:  []string{`{"oddkey":1}`},
+		},
+		{
+			query:   `{}`,
+			matches: []string{`{}`, `{"a":1}`},
+		},
+	}
+
+	for _, tc := range cases {
+		op, err := DefaultParser().FromMongo([]byte(tc.query))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.query, err)
+		}
+		for _, payload := range tc.matches {
+			if res := op.Evaluate([]byte(payload)); !res.Match {
+				t.Fatalf("%s: expected %s to match: %#v", tc.query, payload, res)
+			}
+		}
+		for _, payload := range tc.misses {
+			if res := op.Evaluate([]byte(payload)); res.Match {
+				t.Fatalf("%s: expected %s not to match", tc.query, payload)
+			}
+		}
+	}
+}
+
+func TestFromMongoMatchesText(t *testing.T) {
+	fromMongo, err := DefaultParser().FromMongo([]byte(`{"amount": {"$gt": 100}, "items": {"$elemMatch": {"sku": "A1"}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text, err := ToText(fromMongo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `$.amount > 100 && elemMatch($.items, in($.sku, ["A1"]))`; text != want {
+		t.Fatalf("expected %s, got %s", want, text)
+	}
+	if _, err := DefaultParser().FromText(text); err != nil {
+		t.Fatalf("expected printed tree to parse back: %v", err)
+	}
+}
+
+func TestFromMongoUnsupportedOperators(t *testing.T) {
+	for _, query := range []string{
+		`{"$where": "this.a > 1"}`,
+		`{"$expr": {"$gt": ["$a", "$b"]}}`,
+		`{"a": {"$mod": [4, 0]}}`,
+		`{"a": {"$not": {"$bitsAllSet": 3}}}`,
+		`{"a": {"$regex": "x", "$options": "x"}}`,
+	} {
+		_, err := DefaultParser().FromMongo([]byte(query))
+		if !errors.Is(err, ErrUnsupportedMongoOperator) {
+			t.Fatalf("%s: expected unsupported operator error, got %v", query, err)
+		}
+	}
+
+	for _, query := range []string{
+		`{"a": {"$in": 1}}`,
+		`{"a": {"$type": "binData"}}`,
+		`{"$or": []}`,
+		`{"a": {"$options": "i"}}`,
+		`{"a": {"$size": -1}}`,
+		`{"a": 1} {"b": 2}`,
+	} {
+		if _, err := DefaultParser().FromMongo([]byte(query)); err == nil {
+			t.Fatalf("%s: expected error", query)
+		}
+	}
+}
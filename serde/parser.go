@@ -1,14 +1,18 @@
 package serde
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
 	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
 	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/nested"
 	"gopkg.in/yaml.v3"
 )
 
@@ -17,6 +21,12 @@ const defaultMaxComplexity = 42
 // Parser turns YAML/JSON filter definitions into executable operator trees.
 type Parser struct {
 	maxComplexity int
+	clock         func() time.Time
+	defs          *definitions
+	strict        bool
+	telekom       bool
+	payload       jsonfilter.PayloadPolicy
+	template      bool
 }
 
 // NewParser builds a parser enforcing the configured complexity limit.
@@ -32,15 +42,105 @@ func DefaultParser() Parser {
 	return Parser{maxComplexity: defaultMaxComplexity}
 }
 
+// MaxComplexity returns the largest tree complexity the parser accepts. Every operator counts as one,
+// plus the complexity of its children.
+func (p Parser) MaxComplexity() int {
+	if p.maxComplexity <= 0 {
+		return defaultMaxComplexity
+	}
+	return p.maxComplexity
+}
+
+// Complexity returns the complexity of an operator tree the way the parser counts it: every
+// operator counts as one plus the complexity of its children, and operators shared by several
+// parents count once per parent.
+func Complexity(op jsonfilter.Operator) int {
+	switch typed := op.(type) {
+	case *jsonfilter.ValidatingOperator:
+		return Complexity(typed.Operator())
+	case *logic.Operator:
+		total := 1
+		for _, child := range typed.Children() {
+			total += Complexity(child)
+		}
+		return total
+	case *logic.ConditionalOperator:
+		total := 1 + Complexity(typed.Condition()) + Complexity(typed.Then())
+		if typed.Else() != nil {
+			total += Complexity(typed.Else())
+		}
+		return total
+	case *nested.Operator:
+		return 1 + Complexity(typed.Child())
+	case *nested.ElemMatchOperator:
+		return 1 + Complexity(typed.Child())
+	default:
+		return 1
+	}
+}
+
+// WithClock returns a copy of the parser whose time operators resolve relative bounds such as
+// "now-15m" against clock instead of time.Now.
+func (p Parser) WithClock(clock func() time.Time) Parser {
+	p.clock = clock
+	return p
+}
+
+// WithPayloadPolicy returns a copy of the parser whose operator trees validate each payload once
+// before evaluation and handle invalid JSON according to policy, see
+// jsonfilter.WithPayloadValidation. The default, jsonfilter.PayloadEvaluate, skips validation.
+func (p Parser) WithPayloadPolicy(policy jsonfilter.PayloadPolicy) Parser {
+	p.payload = policy
+	return p
+}
+
+// PayloadPolicy returns how operator trees built by the parser handle payloads that are not valid
+// JSON.
+func (p Parser) PayloadPolicy() jsonfilter.PayloadPolicy {
+	return p.payload
+}
+
 // FromJSON deserializes a JSON filter definition into an operator tree.
+// Numbers are decoded as json.Number so that large integers and decimal amounts keep their exact digits.
 func (p Parser) FromJSON(payload []byte) (jsonfilter.Operator, error) {
 	var root map[string]interface{}
-	if err := json.Unmarshal(payload, &root); err != nil {
-		return nil, fmt.Errorf("parse json: %w", err)
+	if err := decodeJSON(payload, &root); err != nil {
+		return nil, err
 	}
 	return p.parseRoot(root)
 }
 
+// decodeJSON decodes a single JSON document with numbers kept as json.Number. Like json.Unmarshal,
+// it rejects anything but whitespace after the document.
+func decodeJSON(payload []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("parse json: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("unexpected data after top-level value")
+		}
+		return fmt.Errorf("parse json: %w", err)
+	}
+	return nil
+}
+
+// FieldPath maps the text syntax spellings of a field onto gjson paths: `$.name` is the gjson path
+// `name` and a bare `$` is the whole document. Other fields are gjson paths already. Every front end
+// and the builder package read fields through it, so they all accept the same paths.
+func FieldPath(field string) string {
+	switch {
+	case field == "$":
+		return "@this"
+	case strings.HasPrefix(field, "$."):
+		return field[2:]
+	default:
+		return field
+	}
+}
+
 // FromYAML deserializes a YAML filter definition into an operator tree.
 func (p Parser) FromYAML(payload []byte) (jsonfilter.Operator, error) {
 	var root map[string]interface{}
@@ -60,7 +160,39 @@ func (p Parser) parseRoot(node map[string]interface{}) (jsonfilter.Operator, err
 		return nil, errors.New("filter definition cannot be empty")
 	}
 
-	if nested, ok := extractNestedMap(node, "jsonFilter"); ok {
+	if _, ok := node["params"]; ok {
+		return nil, errors.New("filter declares params; load it as a Template and instantiate it with bindings")
+	}
+
+	if _, ok := node[rootSchemaKey]; ok {
+		// Documents may point editors at the published JSON Schema.
+		trimmed := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			if key != rootSchemaKey {
+				trimmed[key] = value
+			}
+		}
+		node = trimmed
+	}
+	if err := p.checkRoot(node); err != nil {
+		return nil, err
+	}
+
+	if rawDefs, ok := node["definitions"]; ok {
+		if p.telekom {
+			return nil, errors.New("definitions are not supported in telekom compatibility mode")
+		}
+		defs, err := newDefinitions(rawDefs)
+		if err != nil {
+			return nil, err
+		}
+		p.defs = defs
+		root, ok := extractNestedMap(node, "jsonFilter")
+		if !ok {
+			return nil, errors.New("definitions require a jsonFilter root operator")
+		}
+		node = root
+	} else if nested, ok := extractNestedMap(node, "jsonFilter"); ok {
 		node = nested
 	}
 
@@ -71,7 +203,15 @@ func (p Parser) parseRoot(node map[string]interface{}) (jsonfilter.Operator, err
 	if complexity > p.maxComplexity {
 		return nil, fmt.Errorf("filter complexity %d exceeds limit %d", complexity, p.maxComplexity)
 	}
-	return op, nil
+	if p.defs != nil {
+		// Compile unreferenced definitions too, so mistakes in them are not silently ignored.
+		for _, name := range p.defs.names() {
+			if _, err := p.resolveDefinition(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return jsonfilter.WithPayloadValidation(op, p.payload), nil
 }
 
 func (p Parser) parseOperator(node map[string]interface{}) (jsonfilter.Operator, int, error) {
@@ -81,9 +221,24 @@ func (p Parser) parseOperator(node map[string]interface{}) (jsonfilter.Operator,
 	if len(node) > 1 {
 		return nil, 0, fmt.Errorf("operator definition contains multiple entries: %v", mapKeys(node))
 	}
+	if compiled, ok := node[compiledKey].(compiledDef); ok {
+		return compiled.op, compiled.complexity, nil
+	}
 
 	for rawName, rawValue := range node {
 		name := strings.ToLower(rawName)
+		if p.telekom {
+			var err error
+			if name, err = telekomOperatorName(rawName); err != nil {
+				return nil, 0, err
+			}
+		}
+		if op, count, err := p.parseRef(name, rawValue); err == nil {
+			return op, count, nil
+		} else if !errors.Is(err, errUnsupportedOperator) {
+			return nil, 0, err
+		}
+
 		if op, count, err := p.parseComparison(name, rawValue); err == nil {
 			return op, count, nil
 		} else if !errors.Is(err, errUnsupportedOperator) {
@@ -96,6 +251,21 @@ func (p Parser) parseOperator(node map[string]interface{}) (jsonfilter.Operator,
 			return nil, 0, err
 		}
 
+		if op, count, err := p.parseConditional(name, rawValue); err == nil {
+			return op, count, nil
+		} else if !errors.Is(err, errUnsupportedOperator) {
+			return nil, 0, err
+		}
+
+		if op, count, err := p.parseNested(name, rawValue); err == nil {
+			return op, count, nil
+		} else if !errors.Is(err, errUnsupportedOperator) {
+			return nil, 0, err
+		}
+
+		if suggestion := suggest(rawName, operatorNames()); suggestion != "" {
+			return nil, 0, fmt.Errorf("operator %s is not supported, did you mean %q?", rawName, suggestion)
+		}
 		return nil, 0, fmt.Errorf("operator %s is not supported", rawName)
 	}
 
@@ -115,17 +285,52 @@ func (p Parser) parseComparison(name string, value interface{}) (jsonfilter.Oper
 		return nil, 0, fmt.Errorf("comparison operator %s expects an object as value", name)
 	}
 
+	if err := p.checkKeys(cfg, "comparison operator "+name, "field", "value", "valueFrom"); err != nil {
+		return nil, 0, err
+	}
+
 	field, _ := cfg["field"].(string)
 	if field == "" {
 		return nil, 0, fmt.Errorf("comparison operator %s requires field attribute", name)
 	}
+	if p.telekom {
+		if field, err = telekomPath(field); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		field = FieldPath(field)
+	}
 
-	val, ok := cfg["value"]
-	if !ok {
+	val, hasValue := cfg["value"]
+	rawRef, hasRef := cfg["valueFrom"]
+	if hasValue && hasRef {
+		return nil, 0, fmt.Errorf("comparison operator %s accepts either value or valueFrom, not both", name)
+	}
+	if !hasValue && !hasRef {
 		return nil, 0, fmt.Errorf("comparison operator %s requires value attribute", name)
 	}
+	if hasRef && p.telekom {
+		return nil, 0, errors.New("valueFrom is not supported in telekom compatibility mode")
+	}
+
+	if hasValue && p.template {
+		if hasPlaceholders(val) {
+			// Built by Template.Instantiate once the placeholders are bound.
+			return &templateLeaf{typ: typ, field: field, value: val, opts: p.comparisonOptions()}, 1, nil
+		}
+		val = substitute(val, nil, typ)
+	}
 
-	op, err := comparison.Instantiate(typ, field, val)
+	var op jsonfilter.Operator
+	if hasRef {
+		ref, _ := rawRef.(string)
+		if ref == "" {
+			return nil, 0, fmt.Errorf("comparison operator %s expects valueFrom to be a json path", name)
+		}
+		op, err = comparison.InstantiateReference(typ, field, FieldPath(ref))
+	} else {
+		op, err = comparison.Instantiate(typ, field, val, p.comparisonOptions()...)
+	}
 	if err != nil {
 		return nil, 0, err
 	}
@@ -137,12 +342,40 @@ func (p Parser) parseComparison(name string, value interface{}) (jsonfilter.Oper
 	return op, 1, nil
 }
 
+func (p Parser) comparisonOptions() []comparison.Option {
+	var opts []comparison.Option
+	if p.clock != nil {
+		opts = append(opts, comparison.WithClock(p.clock))
+	}
+	if p.telekom {
+		opts = append(opts, comparison.WithTextualValues())
+	}
+	return opts
+}
+
 func (p Parser) parseLogic(name string, value interface{}) (jsonfilter.Operator, int, error) {
 	typ, err := logic.ParseType(name)
 	if err != nil {
 		return nil, 0, errUnsupportedOperator
 	}
 
+	count := 0
+	if typ.IsThreshold() {
+		cfg, ok := normalizeMap(value)
+		if !ok {
+			return nil, 0, fmt.Errorf("logic operator %s expects an object with count and of", name)
+		}
+		if err := p.checkKeys(cfg, "logic operator "+name, "count", "of"); err != nil {
+			return nil, 0, err
+		}
+		n, ok := toCount(cfg["count"])
+		if !ok {
+			return nil, 0, fmt.Errorf("logic operator %s requires a non-negative integer count", name)
+		}
+		count = n
+		value = cfg["of"]
+	}
+
 	rawChildren, ok := value.([]interface{})
 	if !ok {
 		return nil, 0, fmt.Errorf("logic operator %s expects an array of child operators", name)
@@ -167,7 +400,169 @@ func (p Parser) parseLogic(name string, value interface{}) (jsonfilter.Operator,
 		children = append(children, childOp)
 	}
 
-	op, err := logic.NewOperator(typ, children)
+	var op *logic.Operator
+	switch {
+	case typ.IsThreshold():
+		op, err = logic.NewThresholdOperator(typ, count, children)
+	case p.telekom:
+		op, err = logic.NewResultListOperator(typ, children)
+	default:
+		op, err = logic.NewOperator(typ, children)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if v := op.Validate(); !v.Valid {
+		return nil, 0, fmt.Errorf("operator %s is invalid: %s", op.Name(), v.CauseDescription)
+	}
+
+	return op, totalComplexity, nil
+}
+
+func (p Parser) parseConditional(name string, value interface{}) (jsonfilter.Operator, int, error) {
+	if name != string(logic.If) {
+		return nil, 0, errUnsupportedOperator
+	}
+
+	cfg, ok := normalizeMap(value)
+	if !ok {
+		return nil, 0, fmt.Errorf("conditional operator expects an object with if, then and optional else")
+	}
+	for key := range cfg {
+		if key != "if" && key != "then" && key != "else" {
+			if suggestion := suggest(key, []string{"if", "then", "else"}); suggestion != "" {
+				return nil, 0, fmt.Errorf("conditional operator does not support attribute %q, did you mean %q?", key, suggestion)
+			}
+			return nil, 0, fmt.Errorf("conditional operator does not support attribute %q", key)
+		}
+	}
+
+	totalComplexity := 1
+	branches := make(map[string]jsonfilter.Operator, 3)
+	for _, key := range []string{"if", "then", "else"} {
+		raw, present := cfg[key]
+		if !present {
+			if key == "else" {
+				continue
+			}
+			return nil, 0, fmt.Errorf("conditional operator requires %s attribute", key)
+		}
+		childMap, ok := normalizeMap(raw)
+		if !ok {
+			return nil, 0, fmt.Errorf("conditional operator %s must be an object", key)
+		}
+		childOp, childComplexity, err := p.parseOperator(childMap)
+		if err != nil {
+			return nil, 0, err
+		}
+		totalComplexity += childComplexity
+		if totalComplexity > p.maxComplexity {
+			return nil, 0, fmt.Errorf("filter complexity %d exceeds limit %d", totalComplexity, p.maxComplexity)
+		}
+		branches[key] = childOp
+	}
+
+	op, err := logic.NewConditionalOperator(branches["if"], branches["then"], branches["else"])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if v := op.Validate(); !v.Valid {
+		return nil, 0, fmt.Errorf("operator %s is invalid: %s", op.Name(), v.CauseDescription)
+	}
+
+	return op, totalComplexity, nil
+}
+
+func (p Parser) parseNested(name string, value interface{}) (jsonfilter.Operator, int, error) {
+	if name == nested.ElemMatchName {
+		return p.parseElemMatch(value)
+	}
+	if name != nested.OperatorName {
+		return nil, 0, errUnsupportedOperator
+	}
+
+	cfg, ok := normalizeMap(value)
+	if !ok {
+		return nil, 0, fmt.Errorf("nested operator expects an object as value")
+	}
+
+	if err := p.checkKeys(cfg, "nested operator", "field", "decode", "filter"); err != nil {
+		return nil, 0, err
+	}
+
+	field, _ := cfg["field"].(string)
+	if field == "" {
+		return nil, 0, fmt.Errorf("nested operator requires field attribute")
+	}
+	field = FieldPath(field)
+
+	decoding := nested.JSON
+	if raw, ok := cfg["decode"]; ok {
+		rawName, _ := raw.(string)
+		parsed, err := nested.ParseDecoding(rawName)
+		if err != nil {
+			return nil, 0, err
+		}
+		decoding = parsed
+	}
+
+	filter, ok := normalizeMap(cfg["filter"])
+	if !ok {
+		return nil, 0, fmt.Errorf("nested operator requires a filter object")
+	}
+	child, childComplexity, err := p.parseOperator(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalComplexity := 1 + childComplexity
+	if totalComplexity > p.maxComplexity {
+		return nil, 0, fmt.Errorf("filter complexity %d exceeds limit %d", totalComplexity, p.maxComplexity)
+	}
+
+	op, err := nested.NewOperator(field, decoding, child)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if v := op.Validate(); !v.Valid {
+		return nil, 0, fmt.Errorf("operator %s is invalid: %s", op.Name(), v.CauseDescription)
+	}
+
+	return op, totalComplexity, nil
+}
+
+func (p Parser) parseElemMatch(value interface{}) (jsonfilter.Operator, int, error) {
+	cfg, ok := normalizeMap(value)
+	if !ok {
+		return nil, 0, fmt.Errorf("elemMatch operator expects an object as value")
+	}
+
+	if err := p.checkKeys(cfg, "elemMatch operator", "field", "filter"); err != nil {
+		return nil, 0, err
+	}
+
+	field, _ := cfg["field"].(string)
+	if field == "" {
+		return nil, 0, fmt.Errorf("elemMatch operator requires field attribute")
+	}
+	field = FieldPath(field)
+
+	filter, ok := normalizeMap(cfg["filter"])
+	if !ok {
+		return nil, 0, fmt.Errorf("elemMatch operator requires a filter object")
+	}
+	child, childComplexity, err := p.parseOperator(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalComplexity := 1 + childComplexity
+	if totalComplexity > p.maxComplexity {
+		return nil, 0, fmt.Errorf("filter complexity %d exceeds limit %d", totalComplexity, p.maxComplexity)
+	}
+
+	op, err := nested.NewElemMatchOperator(field, child)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -217,3 +612,9 @@ func mapKeys(m map[string]interface{}) []string {
 	}
 	return keys
 }
+
+// toCount converts a decoded JSON or YAML number into a non-negative int.
+func toCount(value interface{}) (int, bool) {
+	n, ok := comparison.ToInt(value)
+	return n, ok && n >= 0
+}
@@ -1,6 +1,13 @@
 package serde
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"time"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+)
 
 func TestParserFromJSON(t *testing.T) {
 	parser := DefaultParser()
@@ -79,3 +86,441 @@ func TestParserFromMap(t *testing.T) {
 		t.Fatalf("expected map-parsed operator to match: %#v", res)
 	}
 }
+
+func TestParserSizeOperator(t *testing.T) {
+	parser := DefaultParser()
+	payload := []byte(`
+size:
+  field: items
+  value:
+    min: 1
+    max: 2
+`)
+
+	op, err := parser.FromYAML(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res := op.Evaluate([]byte(`{"items":[1,2,3]}`)); res.Match {
+		t.Fatalf("expected size violation: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"items":[1]}`)); !res.Match {
+		t.Fatalf("expected size match: %#v", res)
+	}
+}
+
+func TestParserValueFrom(t *testing.T) {
+	parser := DefaultParser()
+	payload := []byte(`{"and":[{"eq":{"field":"billing.country","valueFrom":"shipping.country"}},{"gt":{"field":"endDate","valueFrom":"startDate"}}]}`)
+
+	op, err := parser.FromJSON(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"billing":{"country":"DE"},"shipping":{"country":"DE"},"startDate":1,"endDate":2}`)); !res.Match {
+		t.Fatalf("expected field references to match: %#v", res)
+	}
+
+	if _, err := parser.FromJSON([]byte(`{"eq":{"field":"a","value":1,"valueFrom":"b"}}`)); err == nil {
+		t.Fatalf("expected value and valueFrom to be mutually exclusive")
+	}
+}
+
+func TestParserFieldPaths(t *testing.T) {
+	payload := []byte(`{"and":[
+		{"eq":{"field":"$.a","value":1}},
+		{"eq":{"field":"$.b","valueFrom":"$.a"}},
+		{"type":{"field":"$","value":"object"}},
+		{"elemMatch":{"field":"$.items","filter":{"eq":{"field":"$.sku","value":"A1"}}}},
+		{"nested":{"field":"$.raw","filter":{"eq":{"field":"$.id","value":7}}}}
+	]}`)
+	op, err := DefaultParser().FromJSON(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"a":1,"b":1,"items":[{"sku":"A1"}],"raw":"{\"id\":7}"}`)); !res.Match {
+		t.Fatalf("expected $. fields to address the payload: %#v", res)
+	}
+
+	text, err := DefaultParser().FromText(`$.a == 1 && $.b == $.a`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, err := DefaultParser().FromJSON([]byte(`{"and":[{"eq":{"field":"$.a","value":1}},{"eq":{"field":"$.b","valueFrom":"$.a"}}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := ToText(text)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, err := ToText(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected the text and document fields to agree: %q vs %q", got, want)
+	}
+}
+
+func TestParserWithClock(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	parser := DefaultParser().WithClock(func() time.Time { return now })
+	payload := []byte(`{"within":{"field":"createdAt","value":"24h"}}`)
+
+	op, err := parser.FromJSON(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"createdAt":"2024-04-30T18:00:00Z"}`)); !res.Match {
+		t.Fatalf("expected timestamp within the last 24h: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"createdAt":"2024-04-29T18:00:00Z"}`)); res.Match {
+		t.Fatalf("expected timestamp outside the window: %#v", res)
+	}
+}
+
+func TestParserFromJSONKeepsExactNumbers(t *testing.T) {
+	parser := DefaultParser()
+	payload := []byte(`{"and":[{"eq":{"field":"id","value":12345678901234567891}},{"between":{"field":"amount","value":{"min":0.1,"max":0.3}}}]}`)
+
+	op, err := parser.FromJSON(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"id":12345678901234567891,"amount":0.3}`)); !res.Match {
+		t.Fatalf("expected exact match: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"id":12345678901234567890,"amount":0.3}`)); res.Match {
+		t.Fatalf("expected neighbouring id not to match: %#v", res)
+	}
+}
+
+func TestParserFromJSONRejectsTrailingData(t *testing.T) {
+	for _, doc := range []string{
+		`{"eq":{"field":"a","value":1}} garbage`,
+		`{"eq":{"field":"a","value":1}}{"eq":{"field":"b","value":2}}`,
+		`{"eq":{"field":"a","value":1}} ]`,
+	} {
+		if _, err := DefaultParser().FromJSON([]byte(doc)); err == nil || !strings.HasPrefix(err.Error(), "parse json: ") {
+			t.Fatalf("%s: expected trailing data to be rejected, got %v", doc, err)
+		}
+	}
+	if _, err := DefaultParser().FromJSON([]byte("{\"eq\":{\"field\":\"a\",\"value\":1}}\n\t ")); err != nil {
+		t.Fatalf("trailing whitespace should be accepted: %v", err)
+	}
+}
+
+func TestParserSchemaOperator(t *testing.T) {
+	parser := DefaultParser()
+	payload := []byte(`
+schema:
+  field: customer
+  value:
+    type: object
+    required: [tier]
+    properties:
+      tier:
+        enum: [gold, platinum]
+      age:
+        type: integer
+        minimum: 18
+`)
+
+	op, err := parser.FromYAML(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"customer":{"tier":"gold","age":30}}`)); !res.Match {
+		t.Fatalf("expected schema match: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"customer":{"tier":"silver","age":30}}`)); res.Match {
+		t.Fatalf("expected schema violation: %#v", res)
+	}
+}
+
+func TestParserNestedOperator(t *testing.T) {
+	parser := DefaultParser()
+	payload := []byte(`
+nested:
+  field: data
+  decode: json
+  filter:
+    and:
+      - eq:
+          field: id
+          value: 1
+      - eq:
+          field: state
+          value: done
+`)
+
+	op, err := parser.FromYAML(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"data":"{\"id\":1,\"state\":\"done\"}"}`)); !res.Match {
+		t.Fatalf("expected nested filter to match: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"data":"{\"id\":2,\"state\":\"done\"}"}`)); res.Match {
+		t.Fatalf("expected nested filter to fail: %#v", res)
+	}
+
+	if _, err := NewParser(2).FromYAML(payload); err == nil {
+		t.Fatalf("expected nested children to count toward complexity")
+	}
+}
+
+func TestParserThresholdOperators(t *testing.T) {
+	parser := DefaultParser()
+	payload := []byte(`{
+  "atLeast": {
+    "count": 2,
+    "of": [
+      {"eq": {"field": "vpn", "value": true}},
+      {"eq": {"field": "newDevice", "value": true}},
+      {"gt": {"field": "amount", "value": 1000}},
+      {"ne": {"field": "country", "value": "DE"}}
+    ]
+  }
+}`)
+
+	op, err := parser.FromJSON(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"vpn":true,"newDevice":false,"amount":5000,"country":"DE"}`)); !res.Match {
+		t.Fatalf("expected two risk signals to match: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"vpn":false,"newDevice":false,"amount":5000,"country":"DE"}`)); res.Match {
+		t.Fatalf("expected a single risk signal not to match: %#v", res)
+	}
+
+	xor, err := parser.FromYAML([]byte(`
+xor:
+  - eq: {field: card, value: true}
+  - eq: {field: paypal, value: true}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := xor.Evaluate([]byte(`{"card":true,"paypal":true}`)); res.Match {
+		t.Fatalf("expected xor to reject two payment methods: %#v", res)
+	}
+
+	invalid := []string{
+		`{"exactly": [{"eq": {"field": "a", "value": 1}}]}`,
+		`{"exactly": {"count": 2, "of": [{"eq": {"field": "a", "value": 1}}]}}`,
+		`{"atMost": {"count": -1, "of": [{"eq": {"field": "a", "value": 1}}]}}`,
+		`{"atMost": {"count": 1.5, "of": [{"eq": {"field": "a", "value": 1}}]}}`,
+	}
+	for _, filter := range invalid {
+		if _, err := parser.FromJSON([]byte(filter)); err == nil {
+			t.Fatalf("expected %s to be rejected", filter)
+		}
+	}
+}
+
+func TestParserConditionalOperator(t *testing.T) {
+	parser := DefaultParser()
+	op, err := parser.FromYAML([]byte(`
+if:
+  if:
+    eq: {field: country, value: DE}
+  then:
+    eq: {field: currency, value: EUR}
+  else:
+    eq: {field: currency, value: USD}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]bool{
+		`{"country":"DE","currency":"EUR"}`: true,
+		`{"country":"DE","currency":"USD"}`: false,
+		`{"country":"US","currency":"USD"}`: true,
+		`{"country":"US","currency":"EUR"}`: false,
+	}
+	for payload, want := range cases {
+		if res := op.Evaluate([]byte(payload)); res.Match != want {
+			t.Fatalf("%s: expected match=%v, got %#v", payload, want, res)
+		}
+	}
+
+	if _, err := parser.FromYAML([]byte(`
+if:
+  if:
+    eq: {field: a, value: 1}
+`)); err == nil {
+		t.Fatalf("expected missing then branch to be rejected")
+	}
+	if _, err := parser.FromYAML([]byte(`
+if:
+  if: {eq: {field: a, value: 1}}
+  then: {eq: {field: b, value: 1}}
+  otherwise: {eq: {field: c, value: 1}}
+`)); err == nil {
+		t.Fatalf("expected unknown attribute to be rejected")
+	}
+}
+
+func TestParserDefinitions(t *testing.T) {
+	parser := DefaultParser()
+	payload := []byte(`
+definitions:
+  premium:
+    and:
+      - eq: {field: customer.tier, value: gold}
+      - ref: active
+  active:
+    eq: {field: customer.active, value: true}
+jsonFilter:
+  or:
+    - ref: premium
+    - and:
+        - $ref: "#/definitions/premium"
+        - gt: {field: amount, value: 100}
+`)
+
+	op, err := parser.FromYAML(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"customer":{"tier":"gold","active":true}}`)); !res.Match {
+		t.Fatalf("expected premium customer to match: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"customer":{"tier":"gold","active":false}}`)); res.Match {
+		t.Fatalf("expected inactive customer not to match: %#v", res)
+	}
+
+	// or(1) + premium(3) + and(1) + premium(3) + gt(1)
+	if _, err := NewParser(8).FromYAML(payload); err == nil || !strings.Contains(err.Error(), "complexity 9") {
+		t.Fatalf("expected references to count their expanded complexity, got %v", err)
+	}
+}
+
+func TestParserDefinitionsShareInstances(t *testing.T) {
+	op, err := DefaultParser().FromYAML([]byte(`
+definitions:
+  gold:
+    eq: {field: tier, value: gold}
+jsonFilter:
+  and:
+    - ref: gold
+    - ref: gold
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res := op.Evaluate([]byte(`{"tier":"gold"}`))
+	if !res.Match {
+		t.Fatalf("expected shared definition to match: %#v", res)
+	}
+
+	children := op.(*logic.Operator).Children()
+	if children[0] != children[1] {
+		t.Fatalf("expected both references to share one operator instance")
+	}
+}
+
+func TestParserDefinitionErrors(t *testing.T) {
+	cases := map[string]string{
+		"cycle": `
+definitions:
+  a: {ref: b}
+  b: {and: [{ref: c}]}
+  c: {ref: a}
+jsonFilter:
+  ref: a
+`,
+		"missing": `
+definitions:
+  a: {eq: {field: x, value: 1}}
+jsonFilter:
+  ref: b
+`,
+		"unused invalid": `
+definitions:
+  a: {eq: {field: x, value: 1}}
+  broken: {eq: {field: x}}
+jsonFilter:
+  ref: a
+`,
+		"no root": `
+definitions:
+  a: {eq: {field: x, value: 1}}
+`,
+	}
+	for name, payload := range cases {
+		_, err := DefaultParser().FromYAML([]byte(payload))
+		if err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+		if name == "cycle" && !strings.Contains(err.Error(), "a -> b -> c -> a") {
+			t.Fatalf("expected cycle to be reported, got %v", err)
+		}
+	}
+
+	if _, err := DefaultParser().FromYAML([]byte(`ref: a`)); err == nil {
+		t.Fatalf("expected ref without definitions to be rejected")
+	}
+}
+
+func TestParserContextPaths(t *testing.T) {
+	op, err := DefaultParser().FromYAML([]byte(`
+and:
+  - eq: {field: "@headers.x-tenant", value: acme}
+  - eq: {field: "@method", value: POST}
+  - le: {field: amount, valueFrom: "@env.maxAmount"}
+  - nested:
+      field: data
+      filter:
+        eq: {field: region, valueFrom: "@env.region"}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := jsonfilter.NewContext([]byte(`{"amount":150,"data":"{\"region\":\"eu\"}"}`)).
+		With("headers", []byte(`{"x-tenant":"acme"}`)).
+		With("method", []byte(`"POST"`)).
+		With("env", []byte(`{"maxAmount":200,"region":"eu"}`))
+	if res := jsonfilter.EvaluateContext(op, ctx); !res.Match {
+		t.Fatalf("expected context filter to match: %#v", res)
+	}
+
+	ctx.With("env", []byte(`{"maxAmount":100,"region":"eu"}`))
+	if res := jsonfilter.EvaluateContext(op, ctx); res.Match {
+		t.Fatalf("expected amount above context limit not to match: %#v", res)
+	}
+
+	ctx.With("env", []byte(`{"maxAmount":200,"region":"us"}`))
+	if res := jsonfilter.EvaluateContext(op, ctx); res.Match {
+		t.Fatalf("expected nested filter to read context documents: %#v", res)
+	}
+}
+
+func TestContextWithBodyIsolatesDocuments(t *testing.T) {
+	base := jsonfilter.NewContext([]byte(`{}`)).
+		With("headers", []byte(`{"a":1}`)).
+		With("env", []byte(`{"b":1}`))
+	derived := base.WithBody([]byte(`{"c":1}`)).
+		With("headers", []byte(`{"a":2}`)).
+		With("query", []byte(`{"d":1}`))
+	base.With("extra", []byte(`{"e":1}`))
+
+	if doc, _ := base.Document("headers"); string(doc) != `{"a":1}` {
+		t.Fatalf("derived context replaced a document of its origin: %s", doc)
+	}
+	if _, ok := base.Document("query"); ok {
+		t.Fatalf("derived context added a document to its origin")
+	}
+	if _, ok := derived.Document("extra"); ok {
+		t.Fatalf("origin added a document to a derived context")
+	}
+	if doc, _ := derived.Document("query"); string(doc) != `{"d":1}` {
+		t.Fatalf("unexpected query document %s", doc)
+	}
+}
@@ -0,0 +1,117 @@
+package serde
+
+import (
+	"testing"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/tidwall/gjson"
+)
+
+const payloadFilter = `$.user.id == 7 || elemMatch($.items, $.qty > 2) || nested($.raw, $.ok == true)`
+
+var malformedPayloads = []string{
+	`{"user": {"id": 7}, "items": [`,
+	`{"user": {"id": 7}, "items": [{"qty": 3}`,
+	`{"user": {"id": 7},, "items": []}`,
+	`{"user": {"id": 7}} trailing`,
+	`{"user": {"id": 7}`,
+	`{"user": {"id": 07}}`,
+	`{"user": {"id": 7}, "raw": "{\"ok\": true"}`,
+	`{user: {id: 7}}`,
+	`{"user": {"id": 7}, "items": [{"qty": 3}]}` + "\x00",
+	"\xff\xfe{}",
+	`[`,
+	`"`,
+	`nul`,
+	``,
+}
+
+func payloadFilters(tb testing.TB) (plain, noMatch, reject jsonfilter.Operator) {
+	tb.Helper()
+	var err error
+	if plain, err = DefaultParser().FromText(payloadFilter); err != nil {
+		tb.Fatalf("parse: %v", err)
+	}
+	if noMatch, err = DefaultParser().WithPayloadPolicy(jsonfilter.PayloadNoMatch).FromText(payloadFilter); err != nil {
+		tb.Fatalf("parse: %v", err)
+	}
+	if reject, err = DefaultParser().WithPayloadPolicy(jsonfilter.PayloadReject).FromText(payloadFilter); err != nil {
+		tb.Fatalf("parse: %v", err)
+	}
+	return plain, noMatch, reject
+}
+
+func TestMalformedPayloadPolicy(t *testing.T) {
+	plain, noMatch, reject := payloadFilters(t)
+
+	// Without validation gjson reads what precedes the damage, so these payloads match.
+	truncated := []byte(`{"user": {"id": 7}, "items": [`)
+	if res := plain.Evaluate(truncated); !res.Match {
+		t.Fatalf("expected the unvalidated filter to match a truncated payload: %+v", res)
+	}
+	if res := noMatch.Evaluate(truncated); res.Outcome() != jsonfilter.OutcomeNoMatch {
+		t.Fatalf("expected no match, got %+v", res)
+	}
+	res := reject.Evaluate(truncated)
+	if res.Outcome() != jsonfilter.OutcomeError || res.CauseDescription != "payload is not valid JSON" {
+		t.Fatalf("expected a rejected payload, got %+v", res)
+	}
+
+	valid := []byte(`{"user": {"id": 7}}`)
+	for _, op := range []jsonfilter.Operator{noMatch, reject} {
+		if res := op.Evaluate(valid); !res.Match {
+			t.Fatalf("expected a valid payload to match: %+v", res)
+		}
+	}
+
+	ctx := jsonfilter.NewContext(valid).With("headers", []byte(`{"x":`)).WithExplain()
+	res = jsonfilter.EvaluateContext(reject, ctx)
+	if res.Outcome() != jsonfilter.OutcomeError || res.Cause == nil || res.Cause.Code != jsonfilter.CauseInvalidPayload || res.Cause.Path != "@headers" {
+		t.Fatalf("expected the headers document to be rejected, got %+v", res)
+	}
+
+	if text, err := ToText(reject); err != nil || text != payloadFilter {
+		t.Fatalf("unexpected text %q: %v", text, err)
+	}
+	if jsonfilter.WithPayloadValidation(reject, jsonfilter.PayloadEvaluate) != plainOperator(reject) {
+		t.Fatalf("PayloadEvaluate should unwrap the tree")
+	}
+}
+
+func plainOperator(op jsonfilter.Operator) jsonfilter.Operator {
+	return op.(*jsonfilter.ValidatingOperator).Operator()
+}
+
+// FuzzPayloadPolicy checks that no payload makes evaluation panic, that invalid payloads never
+// match under PayloadNoMatch and are errors under PayloadReject, and that valid payloads evaluate
+// as they would without validation.
+func FuzzPayloadPolicy(f *testing.F) {
+	for _, payload := range malformedPayloads {
+		f.Add([]byte(payload))
+	}
+	f.Add([]byte(`{"user": {"id": 7}, "items": [{"qty": 3}], "raw": "{\"ok\": true}"}`))
+	f.Add([]byte(`{"user": {"id": 8}, "items": [{"qty": 1}]}`))
+
+	plain, noMatch, reject := payloadFilters(f)
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		unvalidated := plain.Evaluate(payload)
+		explained := jsonfilter.Explain(plain, payload)
+		if explained.Match != unvalidated.Match {
+			t.Fatalf("explain changed the verdict for %q", payload)
+		}
+
+		dropped, rejected := noMatch.Evaluate(payload), reject.Evaluate(payload)
+		if gjson.ValidBytes(payload) {
+			if dropped.Outcome() != unvalidated.Outcome() || rejected.Outcome() != unvalidated.Outcome() {
+				t.Fatalf("validation changed the verdict for valid payload %q", payload)
+			}
+			return
+		}
+		if dropped.Outcome() != jsonfilter.OutcomeNoMatch {
+			t.Fatalf("expected no match for invalid payload %q, got %+v", payload, dropped)
+		}
+		if rejected.Outcome() != jsonfilter.OutcomeError {
+			t.Fatalf("expected an error for invalid payload %q, got %+v", payload, rejected)
+		}
+	})
+}
@@ -0,0 +1,267 @@
+package serde
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/nested"
+)
+
+// Precedence levels of the text syntax, from loosest to tightest binding.
+const (
+	precOr = iota + 1
+	precAnd
+	precUnary
+	precPrimary
+)
+
+var infixSymbols = map[comparison.Type]string{
+	comparison.Equal:        "==",
+	comparison.NotEqual:     "!=",
+	comparison.LessThan:     "<",
+	comparison.LessEqual:    "<=",
+	comparison.GreaterThan:  ">",
+	comparison.GreaterEqual: ">=",
+}
+
+var logicCallNames = map[logic.Type]string{
+	logic.AtLeast: "atLeast",
+	logic.AtMost:  "atMost",
+	logic.Exactly: "exactly",
+}
+
+// ToText renders an operator tree in the text syntax accepted by FromText. Trees built from
+// definitions with refs are printed with the references expanded. Operators from outside this
+// module cannot be printed. The payload policy of a tree is not part of its text.
+func ToText(op jsonfilter.Operator) (string, error) {
+	text, _, err := printOperator(op)
+	return text, err
+}
+
+func printOperator(op jsonfilter.Operator) (string, int, error) {
+	switch typed := op.(type) {
+	case *jsonfilter.ValidatingOperator:
+		return printOperator(typed.Operator())
+	case *logic.Operator:
+		return printLogic(typed)
+	case *logic.ConditionalOperator:
+		args := []jsonfilter.Operator{typed.Condition(), typed.Then()}
+		if typed.Else() != nil {
+			args = append(args, typed.Else())
+		}
+		text, err := printCall(string(logic.If), nil, args)
+		return text, precPrimary, err
+	case *nested.Operator:
+		field, err := printPath(typed.Field())
+		if err != nil {
+			return "", 0, err
+		}
+		prefix := []string{field}
+		if typed.Decoding() != nested.JSON {
+			prefix = append(prefix, strconv.Quote(string(typed.Decoding())))
+		}
+		text, err := printCall(nested.OperatorName, prefix, []jsonfilter.Operator{typed.Child()})
+		return text, precPrimary, err
+	case *nested.ElemMatchOperator:
+		field, err := printPath(typed.Field())
+		if err != nil {
+			return "", 0, err
+		}
+		text, err := printCall("elemMatch", []string{field}, []jsonfilter.Operator{typed.Child()})
+		return text, precPrimary, err
+	case comparison.Describer:
+		text, err := printComparison(typed.Spec())
+		return text, precPrimary, err
+	default:
+		return "", 0, fmt.Errorf("operator %s cannot be printed as text", op.Name())
+	}
+}
+
+func printLogic(op *logic.Operator) (string, int, error) {
+	children := op.Children()
+	typ := logic.Type(op.Name())
+
+	var sep string
+	var prec int
+	switch {
+	case typ == logic.And && len(children) > 1:
+		sep, prec = " && ", precAnd
+	case typ == logic.Or && len(children) > 1:
+		sep, prec = " || ", precOr
+	case typ == logic.Nor && len(children) == 1:
+		text, childPrec, err := printOperator(children[0])
+		if err != nil {
+			return "", 0, err
+		}
+		if childPrec < precUnary {
+			text = "(" + text + ")"
+		}
+		return "!" + text, precUnary, nil
+	default:
+		name := string(typ)
+		if camel, ok := logicCallNames[typ]; ok {
+			name = camel
+		}
+		var prefix []string
+		if typ.IsThreshold() {
+			prefix = []string{strconv.Itoa(op.Count())}
+		}
+		text, err := printCall(name, prefix, children)
+		return text, precPrimary, err
+	}
+
+	parts := make([]string, len(children))
+	for i, child := range children {
+		text, childPrec, err := printOperator(child)
+		if err != nil {
+			return "", 0, err
+		}
+		// Children of the same kind are parenthesized so that the printed tree keeps its shape.
+		if childPrec <= prec {
+			text = "(" + text + ")"
+		}
+		parts[i] = text
+	}
+	return strings.Join(parts, sep), prec, nil
+}
+
+func printCall(name string, prefix []string, children []jsonfilter.Operator) (string, error) {
+	args := append([]string{}, prefix...)
+	for _, child := range children {
+		text, _, err := printOperator(child)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, text)
+	}
+	return name + "(" + strings.Join(args, ", ") + ")", nil
+}
+
+func printComparison(spec comparison.Spec) (string, error) {
+	field, err := printPath(spec.Field)
+	if err != nil {
+		return "", err
+	}
+
+	var operand string
+	if spec.ValueFrom != "" {
+		operand, err = printPath(spec.ValueFrom)
+	} else if pattern, ok := spec.Value.(string); ok && spec.Type == comparison.Regex && !strings.ContainsAny(pattern, "/\n") {
+		operand = "/" + pattern + "/"
+	} else {
+		operand, err = printLiteral(spec.Value)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if symbol, ok := infixSymbols[spec.Type]; ok {
+		return field + " " + symbol + " " + operand, nil
+	}
+	if spec.Type == comparison.Regex {
+		return field + " =~ " + operand, nil
+	}
+	return string(spec.Type) + "(" + field + ", " + operand + ")", nil
+}
+
+// printPath renders a gjson path so that FromText reads back the same path.
+func printPath(field string) (string, error) {
+	if field == "@this" {
+		return "$", nil
+	}
+	candidate := "$." + field
+	if strings.HasPrefix(field, "@") {
+		candidate = field
+	}
+	if scanPathLength(candidate) == len(candidate) {
+		return candidate, nil
+	}
+	if strings.Contains(field, "`") {
+		return "", fmt.Errorf("path %q cannot be printed as text", field)
+	}
+	return "`" + field + "`", nil
+}
+
+func printLiteral(value interface{}) (string, error) {
+	switch typed := value.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case string:
+		return quoteString(typed), nil
+	case json.Number:
+		return string(typed), nil
+	case float64:
+		if math.IsNaN(typed) || math.IsInf(typed, 0) {
+			return "", fmt.Errorf("value %v cannot be printed as text", typed)
+		}
+		return strconv.FormatFloat(typed, 'g', -1, 64), nil
+	case float32:
+		return printLiteral(float64(typed))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(typed), nil
+	case []string:
+		items := make([]interface{}, len(typed))
+		for i, item := range typed {
+			items[i] = item
+		}
+		return printLiteral(items)
+	case []interface{}:
+		parts := make([]string, len(typed))
+		for i, item := range typed {
+			text, err := printLiteral(item)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = text
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	default:
+		object, ok := normalizeMap(value)
+		if !ok {
+			return "", fmt.Errorf("value of type %T cannot be printed as text", value)
+		}
+		keys := mapKeys(object)
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, key := range keys {
+			text, err := printLiteral(object[key])
+			if err != nil {
+				return "", err
+			}
+			parts[i] = printKey(key) + ": " + text
+		}
+		return "{" + strings.Join(parts, ", ") + "}", nil
+	}
+}
+
+func printKey(key string) string {
+	if key == "" || key == "true" || key == "false" || key == "null" {
+		return quoteString(key)
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c != '_' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(i > 0 && isDigitByte(c)) {
+			return quoteString(key)
+		}
+	}
+	return key
+}
+
+// quoteString renders s as a JSON string without HTML escaping.
+func quoteString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,142 @@
+package serde
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/nested"
+)
+
+// Keys accepted at the filter document root.
+const (
+	rootFilterKey      = "jsonFilter"
+	rootDefinitionsKey = "definitions"
+	rootSchemaKey      = "$schema"
+)
+
+// WithStrict returns a copy of the parser that rejects unknown keys at every level of a filter
+// document instead of ignoring them, so that a typo such as `vaule:` fails with a suggestion rather
+// than silently dropping a constraint. This covers the document root next to jsonFilter and the
+// attributes of every operator.
+func (p Parser) WithStrict() Parser {
+	p.strict = true
+	return p
+}
+
+// checkKeys rejects keys outside allowed when the parser is strict.
+func (p Parser) checkKeys(cfg map[string]interface{}, where string, allowed ...string) error {
+	if !p.strict {
+		return nil
+	}
+	keys := mapKeys(cfg)
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !containsString(allowed, key) {
+			return unknownKeyError(key, where, allowed)
+		}
+	}
+	return nil
+}
+
+func unknownKeyError(key, where string, candidates []string) error {
+	msg := fmt.Sprintf("unknown key %q in %s", key, where)
+	if suggestion := suggest(key, candidates); suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %q?", suggestion)
+	}
+	return errors.New(msg)
+}
+
+// checkRoot rejects siblings of jsonFilter when the parser is strict. A root without jsonFilter is
+// itself an operator, so a key resembling a root key there is most likely a misspelling of it.
+func (p Parser) checkRoot(node map[string]interface{}) error {
+	if !p.strict {
+		return nil
+	}
+	rootKeys := []string{rootFilterKey, rootDefinitionsKey, rootSchemaKey}
+	if _, ok := node[rootFilterKey]; ok {
+		return p.checkKeys(node, "filter root", rootKeys...)
+	}
+	names := operatorNames()
+	for key := range node {
+		if containsString(names, strings.ToLower(key)) || containsString(rootKeys, key) {
+			continue
+		}
+		if suggest(key, rootKeys) != "" {
+			return unknownKeyError(key, "filter root", rootKeys)
+		}
+	}
+	return nil
+}
+
+// operatorNames lists every operator name parseOperator accepts.
+func operatorNames() []string {
+	names := append(comparison.Names(), logic.Names()...)
+	names = append(names, string(logic.If), nested.OperatorName, nested.ElemMatchName, "ref", "$ref")
+	sort.Strings(names)
+	return names
+}
+
+// suggest returns the candidate closest to input, or "" when none is close enough to be a likely
+// typo. Case differences are ignored and an adjacent transposition counts as a single edit; failing
+// that, a candidate that input extends, such as decode for decoding, is suggested.
+func suggest(input string, candidates []string) string {
+	lower := strings.ToLower(input)
+	limit := 2
+	if len(lower) <= 4 {
+		limit = 1
+	}
+
+	best, bestDistance := "", limit+1
+	for _, candidate := range candidates {
+		if d := editDistance(lower, strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best != "" {
+		return best
+	}
+	for _, candidate := range candidates {
+		if len(candidate) >= 3 && strings.HasPrefix(lower, strings.ToLower(candidate)[:len(candidate)-1]) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// editDistance is the optimal string alignment distance between a and b.
+func editDistance(a, b string) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := 0; j <= len(b); j++ {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package serde
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestStrictParserRejectsUnknownKeys(t *testing.T) {
+	cases := map[string]string{
+		`{"eq": {"field": "a", "vaule": 1}}`:                                                           `unknown key "vaule" in comparison operator eq, did you mean "value"?`,
+		`{"eq": {"fields": "a", "value": 1}}`:                                                          `unknown key "fields" in comparison operator eq, did you mean "field"?`,
+		`{"gt": {"field": "a", "valueFrom": "b", "comment": "x"}}`:                                     `unknown key "comment" in comparison operator gt`,
+		`{"jsonFilter": {"eq": {"field": "a", "value": 1}}, "defintions": {}}`:                         `unknown key "defintions" in filter root, did you mean "definitions"?`,
+		`{"jsonFiltr": {"eq": {"field": "a", "value": 1}}}`:                                            `unknown key "jsonFiltr" in filter root, did you mean "jsonFilter"?`,
+		`{"atLeast": {"count": 1, "off": [{"eq": {"field": "a", "value": 1}}]}}`:                       `unknown key "off" in logic operator atleast, did you mean "of"?`,
+		`{"nested": {"field": "a", "decoding": "json", "filter": {"eq": {"field": "b", "value": 1}}}}`: `unknown key "decoding" in nested operator, did you mean "decode"?`,
+		`{"and": [{"elemMatch": {"field": "a", "filer": {"eq": {"field": "b", "value": 1}}}}]}`:        `unknown key "filer" in elemMatch operator, did you mean "filter"?`,
+	}
+	for doc, want := range cases {
+		_, err := DefaultParser().WithStrict().FromJSON([]byte(doc))
+		if err == nil || err.Error() != want {
+			t.Fatalf("%s: expected %q, got %v", doc, want, err)
+		}
+		if _, err := DefaultParser().FromJSON([]byte(doc)); err != nil && strings.Contains(err.Error(), "unknown key") {
+			t.Fatalf("%s: lenient parser should not reject unknown keys: %v", doc, err)
+		}
+	}
+
+	valid := `{"$schema": "./filter.schema.json", "definitions": {"x": {"exists": {"field": "a", "value": true}}}, "jsonFilter": {"ref": "x"}}`
+	if _, err := DefaultParser().WithStrict().FromJSON([]byte(valid)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUnsupportedOperatorSuggestion(t *testing.T) {
+	_, err := DefaultParser().FromJSON([]byte(`{"btween": {"field": "a", "value": {"min": 1, "max": 2}}}`))
+	if err == nil || err.Error() != `operator btween is not supported, did you mean "between"?` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = DefaultParser().FromJSON([]byte(`{"if": {"if": {"exists": {"field": "a", "value": true}}, "than": {"exists": {"field": "b", "value": true}}}}`))
+	if err == nil || !strings.Contains(err.Error(), `did you mean "then"?`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestJSONSchemaCoversOperators(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	defs := schema["definitions"].(map[string]interface{})
+	props := defs["operator"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	var described []string
+	for name := range props {
+		described = append(described, strings.ToLower(name))
+	}
+	sort.Strings(described)
+	if got, want := strings.Join(described, ","), strings.Join(operatorNames(), ","); got != want {
+		t.Fatalf("schema operators differ from parser operators:\n%s\n%s", got, want)
+	}
+
+	// Every internal reference must resolve.
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch typed := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := typed["$ref"].(string); ok && strings.HasPrefix(ref, "#/") {
+				var target interface{} = schema
+				for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+					object, ok := target.(map[string]interface{})
+					if !ok || object[part] == nil {
+						t.Fatalf("unresolved reference %s", ref)
+					}
+					target = object[part]
+				}
+			}
+			for _, child := range typed {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range typed {
+				walk(child)
+			}
+		}
+	}
+	walk(schema)
+}
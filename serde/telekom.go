@@ -0,0 +1,148 @@
+package serde
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+	"github.com/tidwall/gjson"
+)
+
+// telekomOperators lists the operators of telekom/JSON-Filter, spelled as it expects them.
+var telekomOperators = []string{
+	string(logic.And), string(logic.Or),
+	string(comparison.Equal), string(comparison.NotEqual), string(comparison.Regex),
+	string(comparison.LessThan), string(comparison.LessEqual),
+	string(comparison.GreaterThan), string(comparison.GreaterEqual),
+	string(comparison.In), string(comparison.NotIn),
+	string(comparison.Contains), string(comparison.NotContains),
+}
+
+// WithTelekomCompat returns a copy of the parser that reads filters exactly as telekom/JSON-Filter
+// does, so that a filter migrated from it yields the same verdicts:
+//
+//   - only the reference operators are accepted, and their names are case-sensitive;
+//   - fields are JsonPath expressions such as $.a['b c'][0], translated to gjson paths;
+//   - eq, ne and rx compare the textual form of values and rx must match the whole text;
+//   - and and or evaluate every child and report each result in ChildOperators.
+//
+// Extensions of this library, such as definitions and valueFrom, are rejected.
+func (p Parser) WithTelekomCompat() Parser {
+	p.telekom = true
+	return p
+}
+
+// telekomOperatorName checks rawName against the reference operator set.
+func telekomOperatorName(rawName string) (string, error) {
+	if containsString(telekomOperators, rawName) {
+		return rawName, nil
+	}
+	msg := fmt.Sprintf("operator %s is not supported in telekom compatibility mode", rawName)
+	if suggestion := suggest(rawName, telekomOperators); suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %q?", suggestion)
+	}
+	return "", errors.New(msg)
+}
+
+// telekomPath translates a JsonPath expression into the equivalent gjson path. Dot and bracket
+// member access, array indexes and a single [*] or .* wildcard over array elements are supported;
+// deep scans, filters, slices and unions are not.
+func telekomPath(path string) (string, error) {
+	if !strings.HasPrefix(path, "$") {
+		return "", fmt.Errorf("field %q must be a JsonPath starting with $", path)
+	}
+	unsupported := func(what string) error {
+		return fmt.Errorf("field %q: %s is not supported in telekom compatibility mode", path, what)
+	}
+
+	var segments []string
+	wildcard := false
+	rest := path[1:]
+	for rest != "" {
+		var segment string
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			return "", unsupported("deep scan")
+		case strings.HasPrefix(rest, ".*"), strings.HasPrefix(rest, "[*]"):
+			if wildcard {
+				return "", unsupported("more than one wildcard")
+			}
+			wildcard = true
+			segment = "#"
+			rest = strings.TrimPrefix(strings.TrimPrefix(rest, ".*"), "[*]")
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			if end == 0 {
+				return "", fmt.Errorf("field %q has an empty member name", path)
+			}
+			segment = gjson.Escape(rest[1 : end+1])
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['"), strings.HasPrefix(rest, `["`):
+			name, n, err := bracketMember(rest)
+			if err != nil {
+				return "", fmt.Errorf("field %q: %w", path, err)
+			}
+			segment = gjson.Escape(name)
+			rest = rest[n:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return "", fmt.Errorf("field %q has an unterminated bracket", path)
+			}
+			index := rest[1:end]
+			switch {
+			case strings.HasPrefix(index, "?"):
+				return "", unsupported("filter expression " + rest[:end+1])
+			case strings.Contains(index, ":"):
+				return "", unsupported("array slice " + rest[:end+1])
+			case strings.Contains(index, ","):
+				return "", unsupported("union " + rest[:end+1])
+			}
+			if n, err := strconv.Atoi(index); err != nil || n < 0 {
+				return "", unsupported("array index " + rest[:end+1])
+			}
+			segment = index
+			rest = rest[end+1:]
+		default:
+			return "", fmt.Errorf("field %q: unexpected %q", path, rest)
+		}
+		segments = append(segments, segment)
+	}
+
+	// A trailing wildcard selects every element, which is the array itself.
+	if n := len(segments); n > 0 && segments[n-1] == "#" {
+		segments = segments[:n-1]
+	}
+	if len(segments) == 0 {
+		return "@this", nil
+	}
+	return strings.Join(segments, "."), nil
+}
+
+// bracketMember reads a quoted member name such as ['a b'] from the start of s and returns it with
+// the number of bytes consumed.
+func bracketMember(s string) (string, int, error) {
+	quote := s[1]
+	var name strings.Builder
+	for i := 2; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			name.WriteByte(s[i])
+		case c == quote:
+			if i+1 >= len(s) || s[i+1] != ']' {
+				return "", 0, errors.New("quoted member name must be followed by ]")
+			}
+			return name.String(), i + 2, nil
+		default:
+			name.WriteByte(c)
+		}
+	}
+	return "", 0, errors.New("unterminated quoted member name")
+}
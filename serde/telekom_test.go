@@ -0,0 +1,117 @@
+package serde
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+)
+
+func TestTelekomConformanceCorpus(t *testing.T) {
+	raw, err := os.ReadFile("testdata/telekom/corpus.json")
+	if err != nil {
+		t.Fatalf("read corpus: %v", err)
+	}
+	var corpus struct {
+		Payloads map[string]json.RawMessage `json:"payloads"`
+		Cases    []json.RawMessage          `json:"cases"`
+	}
+	if err := json.Unmarshal(raw, &corpus); err != nil {
+		t.Fatalf("decode corpus: %v", err)
+	}
+
+	parser := DefaultParser().WithTelekomCompat()
+	for _, entry := range corpus.Cases {
+		var tc struct {
+			Name     string                       `json:"name"`
+			Filter   json.RawMessage              `json:"filter"`
+			Payload  string                       `json:"payload"`
+			Expected *jsonfilter.EvaluationResult `json:"expected"`
+			Error    string                       `json:"error"`
+		}
+		if err := json.Unmarshal(entry, &tc); err != nil {
+			continue // section comment
+		}
+
+		op, err := parser.FromJSON(tc.Filter)
+		if tc.Error != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.Error) {
+				t.Fatalf("%s: expected error containing %q, got %v", tc.Name, tc.Error, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.Name, err)
+		}
+		payload, ok := corpus.Payloads[tc.Payload]
+		if !ok || tc.Expected == nil {
+			t.Fatalf("%s: case needs a known payload and an expected result", tc.Name)
+		}
+		if msg := compareTelekomResult(*tc.Expected, op.Evaluate(payload)); msg != "" {
+			t.Fatalf("%s: %s", tc.Name, msg)
+		}
+	}
+}
+
+// compareTelekomResult checks actual against expected, ignoring causes expected leaves empty.
+func compareTelekomResult(expected, actual jsonfilter.EvaluationResult) string {
+	switch {
+	case expected.Match != actual.Match || expected.OperatorName != actual.OperatorName:
+		return "expected " + describeResult(expected) + ", got " + describeResult(actual)
+	case expected.CauseDescription != "" && expected.CauseDescription != actual.CauseDescription:
+		return "expected cause " + expected.CauseDescription + ", got " + actual.CauseDescription
+	case len(expected.ChildOperators) != len(actual.ChildOperators):
+		return "expected child results of " + describeResult(expected) + ", got " + describeResult(actual)
+	}
+	for i := range expected.ChildOperators {
+		if msg := compareTelekomResult(expected.ChildOperators[i], actual.ChildOperators[i]); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func describeResult(res jsonfilter.EvaluationResult) string {
+	encoded, _ := json.Marshal(res)
+	return string(encoded)
+}
+
+func TestTelekomCompatKeepsDefaultParser(t *testing.T) {
+	filter := []byte(`{"EQ": {"field": "processing.state", "value": "done"}}`)
+	if _, err := DefaultParser().FromJSON(filter); err != nil {
+		t.Fatalf("expected default parser to accept the filter: %v", err)
+	}
+	if _, err := DefaultParser().WithTelekomCompat().FromJSON(filter); err == nil {
+		t.Fatalf("expected compatibility mode to reject the filter")
+	}
+}
+
+func TestTelekomPath(t *testing.T) {
+	cases := map[string]string{
+		"$":                  "@this",
+		"$.a.b":              "a.b",
+		"$['a.b'][\"c\"]":    `a\.b.c`,
+		"$.items[0].sku":     "items.0.sku",
+		"$.items[*].sku":     "items.#.sku",
+		"$.items[*]":         "items",
+		"$['it\\'s'].x":      `it\'s.x`,
+		"$.a*b":              `a\*b`,
+		"$['a b'].items.*.c": "a b.items.#.c",
+	}
+	for in, want := range cases {
+		got, err := telekomPath(in)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("%s: expected %s, got %s", in, want, got)
+		}
+	}
+	for _, in := range []string{"a.b", "$.", "$.a[", "$['a'", "$.a[*].b[*]", "$.a[-1]", "$.a['x','y']", "$.a[0,1]"} {
+		if _, err := telekomPath(in); err == nil {
+			t.Fatalf("%s: expected error", in)
+		}
+	}
+}
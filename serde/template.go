@@ -0,0 +1,470 @@
+package serde
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/nested"
+	"gopkg.in/yaml.v3"
+)
+
+// ParamType enumerates the types a template parameter can declare.
+type ParamType string
+
+const (
+	ParamString  ParamType = "string"
+	ParamNumber  ParamType = "number"
+	ParamInteger ParamType = "integer"
+	ParamBoolean ParamType = "boolean"
+	ParamArray   ParamType = "array"
+)
+
+var allParamTypes = map[ParamType]struct{}{
+	ParamString:  {},
+	ParamNumber:  {},
+	ParamInteger: {},
+	ParamBoolean: {},
+	ParamArray:   {},
+}
+
+// Template is a filter definition with typed parameters. The definition is decoded, checked and
+// compiled once: operators without placeholders are built when the template is loaded, so
+// structural mistakes such as an unknown operator or a bad regex are reported by TemplateFromMap.
+// Instantiate only builds the comparisons whose value holds placeholders and reassembles their
+// parents.
+//
+// Placeholders may only appear inside `value` attributes. A value that consists of a single
+// placeholder is replaced by the bound value itself, e.g. a number or a list; placeholders embedded
+// in a longer string are interpolated as text, escaped so that the bound text matches literally in
+// rx and like patterns. `$${` stands for a literal `${`.
+type Template struct {
+	params map[string]ParamType
+	shape  jsonfilter.Operator
+}
+
+// templateLeaf stands in for a comparison whose value holds placeholders until Instantiate binds
+// them. It never leaves a Template.
+type templateLeaf struct {
+	typ   comparison.Type
+	field string
+	value interface{}
+	opts  []comparison.Option
+}
+
+func (l *templateLeaf) Name() string {
+	return string(l.typ)
+}
+
+func (l *templateLeaf) Evaluate([]byte) jsonfilter.EvaluationResult {
+	return jsonfilter.EvaluationErrorResult(l.Name(), "template parameters are not bound")
+}
+
+func (l *templateLeaf) Validate() jsonfilter.ValidationResult {
+	return jsonfilter.ValidValidationResult(l.Name())
+}
+
+func (l *templateLeaf) bind(bindings map[string]interface{}) (jsonfilter.Operator, error) {
+	op, err := comparison.Instantiate(l.typ, l.field, substitute(l.value, bindings, l.typ), l.opts...)
+	if err != nil {
+		return nil, err
+	}
+	if v := op.Validate(); !v.Valid {
+		return nil, fmt.Errorf("operator %s is invalid: %s", op.Name(), v.CauseDescription)
+	}
+	return op, nil
+}
+
+// TemplateFromJSON decodes a JSON filter template.
+func (p Parser) TemplateFromJSON(payload []byte) (*Template, error) {
+	var root map[string]interface{}
+	if err := decodeJSON(payload, &root); err != nil {
+		return nil, err
+	}
+	return p.TemplateFromMap(root)
+}
+
+// TemplateFromYAML decodes a YAML filter template.
+func (p Parser) TemplateFromYAML(payload []byte) (*Template, error) {
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(payload, &root); err != nil {
+		return nil, fmt.Errorf("parse yaml: %w", err)
+	}
+	return p.TemplateFromMap(root)
+}
+
+// TemplateFromMap builds a template from an already unmarshaled map. The params section declares
+// each parameter's type, e.g. `params: {tenant: string, minAmount: number}`.
+func (p Parser) TemplateFromMap(root map[string]interface{}) (*Template, error) {
+	if root == nil {
+		return nil, errors.New("filter definition cannot be empty")
+	}
+
+	params := map[string]ParamType{}
+	if raw, ok := root["params"]; ok {
+		declared, ok := normalizeMap(raw)
+		if !ok {
+			return nil, fmt.Errorf("params must map parameter names to types")
+		}
+		for name, rawType := range declared {
+			typeName, _ := rawType.(string)
+			typ := ParamType(typeName)
+			if _, ok := allParamTypes[typ]; !ok {
+				return nil, fmt.Errorf("parameter %s has unsupported type %v", name, rawType)
+			}
+			params[name] = typ
+		}
+	}
+
+	body := make(map[string]interface{}, len(root))
+	for key, value := range root {
+		if key != "params" {
+			body[key] = value
+		}
+	}
+	if err := checkPlaceholders(body, params, false); err != nil {
+		return nil, err
+	}
+
+	p.template = true
+	shape, err := p.parseRoot(body)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{params: params, shape: shape}, nil
+}
+
+// Params returns the declared parameters in sorted order.
+func (t *Template) Params() []string {
+	names := make([]string, 0, len(t.params))
+	for name := range t.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParamType returns the declared type of a parameter.
+func (t *Template) ParamType(name string) (ParamType, bool) {
+	typ, ok := t.params[name]
+	return typ, ok
+}
+
+// Instantiate type-checks bindings against the declared parameters and builds the operator tree.
+// Every parameter must be bound and unknown bindings are rejected. Operators without placeholders
+// are shared with the template and with every other instance.
+func (t *Template) Instantiate(bindings map[string]interface{}) (jsonfilter.Operator, error) {
+	for name := range bindings {
+		if _, ok := t.params[name]; !ok {
+			return nil, fmt.Errorf("parameter %s is not declared", name)
+		}
+	}
+	for _, name := range t.Params() {
+		value, ok := bindings[name]
+		if !ok {
+			return nil, fmt.Errorf("parameter %s is not bound", name)
+		}
+		if err := checkParamType(name, t.params[name], value); err != nil {
+			return nil, err
+		}
+	}
+
+	return bindShape(t.shape, bindings, map[jsonfilter.Operator]jsonfilter.Operator{})
+}
+
+// bindShape builds the leaves of op that hold placeholders and rebuilds the operators above them.
+// Operators shared through definitions are rebuilt once and stay shared.
+func bindShape(op jsonfilter.Operator, bindings map[string]interface{}, bound map[jsonfilter.Operator]jsonfilter.Operator) (jsonfilter.Operator, error) {
+	switch op.(type) {
+	case *templateLeaf, *logic.Operator, *logic.ConditionalOperator, *nested.Operator, *nested.ElemMatchOperator, *jsonfilter.ValidatingOperator:
+	default:
+		return op, nil
+	}
+	if done, ok := bound[op]; ok {
+		return done, nil
+	}
+
+	var (
+		result jsonfilter.Operator
+		err    error
+	)
+	switch typed := op.(type) {
+	case *templateLeaf:
+		result, err = typed.bind(bindings)
+	case *logic.Operator:
+		children := make([]jsonfilter.Operator, len(typed.Children()))
+		changed := false
+		for i, child := range typed.Children() {
+			if children[i], err = bindShape(child, bindings, bound); err != nil {
+				return nil, err
+			}
+			changed = changed || children[i] != child
+		}
+		result = op
+		if changed {
+			result, err = typed.WithChildren(children)
+		}
+	case *logic.ConditionalOperator:
+		var condition, then, otherwise jsonfilter.Operator
+		if condition, err = bindShape(typed.Condition(), bindings, bound); err != nil {
+			return nil, err
+		}
+		if then, err = bindShape(typed.Then(), bindings, bound); err != nil {
+			return nil, err
+		}
+		if typed.Else() != nil {
+			if otherwise, err = bindShape(typed.Else(), bindings, bound); err != nil {
+				return nil, err
+			}
+		}
+		result = op
+		if condition != typed.Condition() || then != typed.Then() || otherwise != typed.Else() {
+			result, err = logic.NewConditionalOperator(condition, then, otherwise)
+		}
+	case *nested.Operator:
+		var child jsonfilter.Operator
+		if child, err = bindShape(typed.Child(), bindings, bound); err != nil {
+			return nil, err
+		}
+		result = op
+		if child != typed.Child() {
+			result, err = nested.NewOperator(typed.Field(), typed.Decoding(), child)
+		}
+	case *nested.ElemMatchOperator:
+		var child jsonfilter.Operator
+		if child, err = bindShape(typed.Child(), bindings, bound); err != nil {
+			return nil, err
+		}
+		result = op
+		if child != typed.Child() {
+			result, err = nested.NewElemMatchOperator(typed.Field(), child)
+		}
+	case *jsonfilter.ValidatingOperator:
+		var inner jsonfilter.Operator
+		if inner, err = bindShape(typed.Operator(), bindings, bound); err != nil {
+			return nil, err
+		}
+		result = jsonfilter.WithPayloadValidation(inner, typed.Policy())
+	}
+	if err != nil {
+		return nil, err
+	}
+	bound[op] = result
+	return result, nil
+}
+
+// checkPlaceholders verifies that placeholders reference declared parameters and only appear in
+// value positions.
+func checkPlaceholders(node interface{}, params map[string]ParamType, inValue bool) error {
+	switch typed := node.(type) {
+	case string:
+		for _, name := range placeholders(typed) {
+			if !inValue {
+				return fmt.Errorf("placeholder ${%s} may only appear in a value attribute", name)
+			}
+			typ, ok := params[name]
+			if !ok {
+				return fmt.Errorf("placeholder ${%s} references an undeclared parameter", name)
+			}
+			if typed != "${"+name+"}" && typ == ParamArray {
+				return fmt.Errorf("array parameter %s cannot be interpolated into a string", name)
+			}
+		}
+	case []interface{}:
+		for _, item := range typed {
+			if err := checkPlaceholders(item, params, inValue); err != nil {
+				return err
+			}
+		}
+	default:
+		m, ok := normalizeMap(node)
+		if !ok {
+			return nil
+		}
+		for key, value := range m {
+			if len(placeholders(key)) > 0 {
+				return fmt.Errorf("placeholders are not allowed in attribute names")
+			}
+			if err := checkPlaceholders(value, params, inValue || key == "value"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasPlaceholders reports whether a value attribute references any parameter.
+func hasPlaceholders(node interface{}) bool {
+	switch typed := node.(type) {
+	case string:
+		return len(placeholders(typed)) > 0
+	case []interface{}:
+		for _, item := range typed {
+			if hasPlaceholders(item) {
+				return true
+			}
+		}
+	default:
+		m, _ := normalizeMap(node)
+		for _, value := range m {
+			if hasPlaceholders(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// substitute returns a copy of the value attribute of a typ comparison with its placeholders
+// replaced and `$${` unescaped. Text interpolated into rx and like patterns is quoted so that it
+// matches literally.
+func substitute(node interface{}, bindings map[string]interface{}, typ comparison.Type) interface{} {
+	switch typed := node.(type) {
+	case string:
+		if !strings.Contains(typed, "${") {
+			return typed
+		}
+		names := placeholders(typed)
+		if len(names) == 1 && typed == "${"+names[0]+"}" {
+			return bindings[names[0]]
+		}
+		return expand(typed, func(name string) string {
+			text := formatBinding(bindings[name])
+			switch typ {
+			case comparison.Regex:
+				return regexp.QuoteMeta(text)
+			case comparison.Like:
+				return globEscaper.Replace(text)
+			}
+			return text
+		})
+	case []interface{}:
+		out := make([]interface{}, len(typed))
+		for i, item := range typed {
+			out[i] = substitute(item, bindings, typ)
+		}
+		return out
+	default:
+		m, ok := normalizeMap(node)
+		if !ok {
+			return node
+		}
+		out := make(map[string]interface{}, len(m))
+		for key, value := range m {
+			out[key] = substitute(value, bindings, typ)
+		}
+		return out
+	}
+}
+
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+
+// placeholders lists the parameter names referenced as ${name} in s.
+func placeholders(s string) []string {
+	var names []string
+	expand(s, func(name string) string {
+		names = append(names, name)
+		return ""
+	})
+	return names
+}
+
+// expand replaces every ${name} in s with replace(name) and every `$${` with a literal `${`.
+func expand(s string, replace func(name string) string) string {
+	var out strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			out.WriteString(s)
+			return out.String()
+		}
+		if start > 0 && s[start-1] == '$' {
+			out.WriteString(s[:start-1])
+			out.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			out.WriteString(s)
+			return out.String()
+		}
+		out.WriteString(s[:start])
+		out.WriteString(replace(s[start+2 : start+end]))
+		s = s[start+end+1:]
+	}
+}
+
+func checkParamType(name string, typ ParamType, value interface{}) error {
+	ok := false
+	switch typ {
+	case ParamString:
+		_, ok = value.(string)
+	case ParamBoolean:
+		_, ok = value.(bool)
+	case ParamNumber:
+		_, ok = numberText(value)
+	case ParamInteger:
+		var text string
+		if text, ok = numberText(value); ok {
+			f, err := strconv.ParseFloat(text, 64)
+			ok = err == nil && f == math.Trunc(f)
+		}
+	case ParamArray:
+		switch value.(type) {
+		case []interface{}, []string:
+			ok = true
+		}
+	}
+	if !ok {
+		return fmt.Errorf("parameter %s expects %s, got %T", name, typ, value)
+	}
+	return nil
+}
+
+// numberText formats numeric bindings; numeric strings are not accepted as numbers.
+func numberText(value interface{}) (string, bool) {
+	switch typed := value.(type) {
+	case json.Number:
+		if _, err := strconv.ParseFloat(string(typed), 64); err != nil {
+			return "", false
+		}
+		return string(typed), true
+	case float64:
+		if math.IsNaN(typed) || math.IsInf(typed, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(typed, 'g', -1, 64), true
+	case float32:
+		return numberText(float64(typed))
+	case int:
+		return strconv.Itoa(typed), true
+	case int32:
+		return strconv.FormatInt(int64(typed), 10), true
+	case int64:
+		return strconv.FormatInt(typed, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(typed), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(typed), 10), true
+	case uint64:
+		return strconv.FormatUint(typed, 10), true
+	default:
+		return "", false
+	}
+}
+
+func formatBinding(value interface{}) string {
+	if text, ok := numberText(value); ok {
+		return text
+	}
+	return fmt.Sprint(value)
+}
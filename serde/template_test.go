@@ -0,0 +1,198 @@
+package serde
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+)
+
+const tenantTemplate = `
+params:
+  tenant: string
+  minAmount: number
+  networks: array
+jsonFilter:
+  and:
+    - eq: {field: tenant, value: "${tenant}"}
+    - ge: {field: amount, value: "${minAmount}"}
+    - sw: {field: account, value: "${tenant}-"}
+    - cidr: {field: ip, value: "${networks}"}
+`
+
+func TestTemplateInstantiate(t *testing.T) {
+	tpl, err := DefaultParser().TemplateFromYAML([]byte(tenantTemplate))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tpl.Params(); strings.Join(got, ",") != "minAmount,networks,tenant" {
+		t.Fatalf("unexpected params %v", got)
+	}
+
+	acme, err := tpl.Instantiate(map[string]interface{}{
+		"tenant":    "acme",
+		"minAmount": 100,
+		"networks":  []interface{}{"10.0.0.0/8"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	globex, err := tpl.Instantiate(map[string]interface{}{
+		"tenant":    "globex",
+		"minAmount": json.Number("5.5"),
+		"networks":  []string{"192.168.0.0/16"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	payload := []byte(`{"tenant":"acme","amount":150,"account":"acme-42","ip":"10.1.2.3"}`)
+	if res := acme.Evaluate(payload); !res.Match {
+		t.Fatalf("expected acme filter to match: %#v", res)
+	}
+	if res := globex.Evaluate(payload); res.Match {
+		t.Fatalf("expected globex filter not to match acme payload: %#v", res)
+	}
+	if res := acme.Evaluate([]byte(`{"tenant":"acme","amount":50,"account":"acme-42","ip":"10.1.2.3"}`)); res.Match {
+		t.Fatalf("expected amount below bound not to match: %#v", res)
+	}
+}
+
+func TestTemplateBindingErrors(t *testing.T) {
+	tpl, err := DefaultParser().TemplateFromYAML([]byte(tenantTemplate))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{"tenant": "acme", "minAmount": 1, "networks": []interface{}{"10.0.0.0/8"}}
+	}
+	cases := map[string]func(map[string]interface{}){
+		"missing":    func(b map[string]interface{}) { delete(b, "tenant") },
+		"unknown":    func(b map[string]interface{}) { b["region"] = "eu" },
+		"string":     func(b map[string]interface{}) { b["tenant"] = 42 },
+		"number":     func(b map[string]interface{}) { b["minAmount"] = "100" },
+		"array":      func(b map[string]interface{}) { b["networks"] = "10.0.0.0/8" },
+		"bad prefix": func(b map[string]interface{}) { b["networks"] = []interface{}{"nope"} },
+	}
+	for name, mutate := range cases {
+		bindings := valid()
+		mutate(bindings)
+		if _, err := tpl.Instantiate(bindings); err == nil {
+			t.Fatalf("%s: expected instantiation to fail", name)
+		}
+	}
+}
+
+func TestTemplateDefinitionErrors(t *testing.T) {
+	cases := map[string]string{
+		"undeclared":  `{"jsonFilter": {"eq": {"field": "a", "value": "${b}"}}}`,
+		"field":       `{"params": {"f": "string"}, "jsonFilter": {"eq": {"field": "${f}", "value": 1}}}`,
+		"bad type":    `{"params": {"f": "date"}, "jsonFilter": {"eq": {"field": "a", "value": "${f}"}}}`,
+		"interpolate": `{"params": {"f": "array"}, "jsonFilter": {"eq": {"field": "a", "value": "x${f}"}}}`,
+		"operator":    `{"params": {"f": "string"}, "jsonFilter": {"and": [{"eq": {"field": "a", "value": "${f}"}}, {"eqq": {"field": "b", "value": 1}}]}}`,
+		"regex":       `{"params": {"f": "string"}, "jsonFilter": {"and": [{"eq": {"field": "a", "value": "${f}"}}, {"rx": {"field": "b", "value": "("}}]}}`,
+		"no field":    `{"params": {"f": "string"}, "jsonFilter": {"eq": {"value": "${f}"}}}`,
+		"complexity":  `{"params": {"f": "string"}, "jsonFilter": {"nested": {"field": "a", "filter": {"nested": {"field": "b", "filter": {"eq": {"field": "c", "value": "${f}"}}}}}}}`,
+	}
+	for name, payload := range cases {
+		if _, err := NewParser(2).TemplateFromJSON([]byte(payload)); err == nil {
+			t.Fatalf("%s: expected template to be rejected", name)
+		}
+	}
+
+	if _, err := DefaultParser().TemplateFromJSON([]byte(`{"params": {"f": "string"}, "jsonFilter": {"eq": {"field": "a", "value": "${f}"}}} garbage`)); err == nil {
+		t.Fatalf("expected trailing data after a template to be rejected")
+	}
+	if _, err := DefaultParser().FromJSON([]byte(`{"params": {"f": "string"}, "jsonFilter": {"eq": {"field": "a", "value": "${f}"}}}`)); err == nil {
+		t.Fatalf("expected plain parsing of a template to be rejected")
+	}
+}
+
+func TestTemplateSharesOperatorsWithoutPlaceholders(t *testing.T) {
+	tpl, err := DefaultParser().TemplateFromYAML([]byte(`
+params: {tenant: string}
+definitions:
+  scoped: {eq: {field: tenant, value: "${tenant}"}}
+jsonFilter:
+  and:
+    - ref: scoped
+    - exists: {field: amount, value: true}
+    - nor: [{ref: scoped}]
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	acme, err := tpl.Instantiate(map[string]interface{}{"tenant": "acme"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	globex, err := tpl.Instantiate(map[string]interface{}{"tenant": "globex"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a, g := acme.(*logic.Operator).Children(), globex.(*logic.Operator).Children()
+	if a[1] != g[1] {
+		t.Fatalf("expected the literal leaf to be shared between instances")
+	}
+	if a[0] == g[0] || a[0] != a[2].(*logic.Operator).Children()[0] {
+		t.Fatalf("expected each instance to bind the definition once and share it between its refs")
+	}
+	if res := acme.Evaluate([]byte(`{"tenant":"acme","amount":1}`)); res.Match {
+		t.Fatalf("expected the contradiction not to match: %#v", res)
+	}
+}
+
+func TestTemplateQuotesInterpolatedPatterns(t *testing.T) {
+	tpl, err := DefaultParser().TemplateFromYAML([]byte(`
+params: {tenant: string}
+jsonFilter:
+  or:
+    - rx: {field: account, value: "^${tenant}-[0-9]+$"}
+    - like: {field: host, value: "${tenant}.*.example.com"}
+    - rx: {field: code, value: "^a$${1,3}"}
+    - eq: {field: raw, value: "$${tenant}"}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	op, err := tpl.Instantiate(map[string]interface{}{"tenant": ".*|x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := map[string]bool{
+		`{"account":"evil-1"}`:               false,
+		`{"account":".*|x-1"}`:               true,
+		`{"host":"anything.eu.example.com"}`: false,
+		`{"host":".*|x.eu.example.com"}`:     true,
+		`{"code":"a"}`:                       true,
+		`{"code":"aa"}`:                      false,
+		`{"raw":"${tenant}"}`:                true,
+		`{"raw":".*|x"}`:                     false,
+	}
+	for payload, want := range cases {
+		if res := op.Evaluate([]byte(payload)); res.Match != want {
+			t.Fatalf("%s: expected match %v, got %#v", payload, want, res)
+		}
+	}
+
+	glob, err := DefaultParser().TemplateFromYAML([]byte(`
+params: {tenant: string}
+jsonFilter: {like: {field: host, value: "${tenant}.*"}}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	op, err = glob.Instantiate(map[string]interface{}{"tenant": `a*b?\`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := op.Evaluate([]byte(`{"host":"a*b?\\.eu"}`)); !res.Match {
+		t.Fatalf("expected glob metacharacters to match literally: %#v", res)
+	}
+	if res := op.Evaluate([]byte(`{"host":"axbz\\.eu"}`)); res.Match {
+		t.Fatalf("expected glob metacharacters not to act as wildcards: %#v", res)
+	}
+}
@@ -0,0 +1,777 @@
+package serde
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	jsonfilter "github.com/andrey-viktorov/jsonfilter-go"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/comparison"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/logic"
+	"github.com/andrey-viktorov/jsonfilter-go/operator/nested"
+)
+
+// SyntaxError reports a problem in a text filter expression. Line and Column are 1-based; columns
+// count characters, not bytes.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Line == 1 {
+		return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+	}
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// FromText compiles a text filter expression such as
+//
+//	$.state == "done" && ($.id =~ /^ABC/ || $.amount >= 100)
+//
+// into the same operator tree FromYAML builds for the equivalent definition. `||` binds weaker than
+// `&&`, which binds weaker than `!`. Paths start with `$.` (or are a bare `$`) for the payload and
+// `@name.` for context documents; paths containing spaces or operator characters are written in
+// backticks. Comparisons are infix (`==`, `!=`, `<`, `<=`, `>`, `>=`, `=~`) or calls such as
+// `cidr($.ip, ["10.0.0.0/8"])`, and a path on the right-hand side compares against another field.
+// Logic operators other than `&&`, `||` and `!` are calls as well: `xor(a, b)`, `atLeast(2, a, b, c)`,
+// `if(cond, then, else)`, `nested($.data, "json", expr)` and `elemMatch($.items, expr)`.
+func (p Parser) FromText(expr string) (jsonfilter.Operator, error) {
+	tp := &textParser{parser: p, lex: textLexer{src: expr}}
+	if err := tp.advance(); err != nil {
+		return nil, err
+	}
+	node, err := tp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tp.tok.kind != tokEOF {
+		return nil, tp.errorf(tp.tok.pos, "unexpected %s", tp.tok)
+	}
+
+	op, complexity, err := p.parseOperator(node.def)
+	if err != nil {
+		return nil, tp.errorf(node.pos, "%v", err)
+	}
+	if complexity > p.maxComplexity {
+		return nil, fmt.Errorf("filter complexity %d exceeds limit %d", complexity, p.maxComplexity)
+	}
+	return jsonfilter.WithPayloadValidation(op, p.payload), nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokLBrace
+	tokRBrace
+	tokComma
+	tokColon
+	tokAnd
+	tokOr
+	tokNot
+	tokCompare
+	tokString
+	tokNumber
+	tokRegex
+	tokPath
+	tokIdent
+)
+
+type token struct {
+	kind  tokenKind
+	pos   int
+	text  string
+	value interface{}
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return "string " + t.text
+	case tokNumber:
+		return "number " + t.text
+	case tokPath:
+		return "path " + t.text
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// infixTypes maps infix comparison symbols to operator names.
+var infixTypes = map[string]comparison.Type{
+	"==": comparison.Equal,
+	"!=": comparison.NotEqual,
+	"<":  comparison.LessThan,
+	"<=": comparison.LessEqual,
+	">":  comparison.GreaterThan,
+	">=": comparison.GreaterEqual,
+	"=~": comparison.Regex,
+}
+
+var punctuation = map[byte]tokenKind{
+	'(': tokLParen, ')': tokRParen, '[': tokLBracket, ']': tokRBracket,
+	'{': tokLBrace, '}': tokRBrace, ',': tokComma, ':': tokColon, '!': tokNot,
+}
+
+type textLexer struct {
+	src  string
+	pos  int
+	last tokenKind
+	op   string
+}
+
+func (l *textLexer) next() (token, error) {
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		l.pos += size
+	}
+	start := l.pos
+	tok, err := l.scan()
+	if err != nil {
+		return token{}, err
+	}
+	tok.pos = start
+	l.last = tok.kind
+	if tok.kind == tokCompare {
+		l.op = tok.text
+	}
+	return tok, nil
+}
+
+func (l *textLexer) scan() (token, error) {
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF}, nil
+	}
+	rest := l.src[l.pos:]
+	c := rest[0]
+
+	for _, sym := range []string{"&&", "||", "==", "!=", "<=", ">=", "=~"} {
+		if strings.HasPrefix(rest, sym) {
+			l.pos += 2
+			switch sym {
+			case "&&":
+				return token{kind: tokAnd, text: sym}, nil
+			case "||":
+				return token{kind: tokOr, text: sym}, nil
+			default:
+				return token{kind: tokCompare, text: sym}, nil
+			}
+		}
+	}
+
+	if kind, ok := punctuation[c]; ok {
+		l.pos++
+		return token{kind: kind, text: string(c)}, nil
+	}
+
+	switch {
+	case c == '<' || c == '>':
+		l.pos++
+		return token{kind: tokCompare, text: string(c)}, nil
+	case c == '"':
+		return l.scanString()
+	case c == '/' && l.last == tokCompare && l.op == "=~":
+		return l.scanRegex()
+	case c == '-' || isDigitByte(c):
+		return l.scanNumber()
+	case c == '$' || c == '@' || c == '`':
+		return l.scanPath()
+	case c == '_' || unicode.IsLetter(rune(c)):
+		end := 1
+		for end < len(rest) && (rest[end] == '_' || isDigitByte(rest[end]) || unicode.IsLetter(rune(rest[end]))) {
+			end++
+		}
+		l.pos += end
+		return token{kind: tokIdent, text: rest[:end]}, nil
+	default:
+		r, _ := utf8.DecodeRuneInString(rest)
+		return token{}, l.errorf(l.pos, "unexpected character %q", r)
+	}
+}
+
+func (l *textLexer) scanString() (token, error) {
+	start := l.pos
+	i := l.pos + 1
+	for i < len(l.src) && l.src[i] != '"' {
+		if l.src[i] == '\\' {
+			i++
+		}
+		i++
+	}
+	if i >= len(l.src) {
+		return token{}, l.errorf(start, "unterminated string")
+	}
+	text := l.src[start : i+1]
+	var value string
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return token{}, l.errorf(start, "invalid string %s", text)
+	}
+	l.pos = i + 1
+	return token{kind: tokString, text: text, value: value}, nil
+}
+
+// scanRegex reads /pattern/flags. `\/` stands for a slash; other escapes are passed to the regex
+// engine unchanged. Flags i, m and s become a (?flags) prefix.
+func (l *textLexer) scanRegex() (token, error) {
+	start := l.pos
+	var pattern strings.Builder
+	i := l.pos + 1
+	for ; i < len(l.src) && l.src[i] != '/'; i++ {
+		if l.src[i] == '\\' && i+1 < len(l.src) {
+			if l.src[i+1] != '/' {
+				pattern.WriteByte('\\')
+			}
+			i++
+		}
+		pattern.WriteByte(l.src[i])
+	}
+	if i >= len(l.src) {
+		return token{}, l.errorf(start, "unterminated regular expression")
+	}
+	i++
+	flags := ""
+	for i < len(l.src) && strings.IndexByte("ims", l.src[i]) >= 0 {
+		flags += string(l.src[i])
+		i++
+	}
+	value := pattern.String()
+	if flags != "" {
+		value = "(?" + flags + ")" + value
+	}
+	text := l.src[start:i]
+	l.pos = i
+	return token{kind: tokRegex, text: text, value: value}, nil
+}
+
+func (l *textLexer) scanNumber() (token, error) {
+	start := l.pos
+	end := scanNumberLiteral(l.src[start:])
+	if end == 0 {
+		return token{}, l.errorf(start, "invalid number")
+	}
+	l.pos += end
+	text := l.src[start:l.pos]
+	return token{kind: tokNumber, text: text, value: json.Number(text)}, nil
+}
+
+// scanNumberLiteral returns the length of the JSON number at the start of s, or 0.
+func scanNumberLiteral(s string) int {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	digits := i
+	for i < len(s) && isDigitByte(s[i]) {
+		i++
+	}
+	if i == digits {
+		return 0
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		frac := i
+		for i < len(s) && isDigitByte(s[i]) {
+			i++
+		}
+		if i == frac {
+			return 0
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		exp := i
+		for i < len(s) && isDigitByte(s[i]) {
+			i++
+		}
+		if i == exp {
+			return 0
+		}
+	}
+	return i
+}
+
+// scanPath reads `$.path`, `$`, `@doc.path` or a backtick-quoted raw path. The token value is the
+// operator field, which the parser maps onto a gjson path with FieldPath.
+func (l *textLexer) scanPath() (token, error) {
+	start := l.pos
+	if l.src[start] == '`' {
+		end := strings.IndexByte(l.src[start+1:], '`')
+		if end < 0 {
+			return token{}, l.errorf(start, "unterminated quoted path")
+		}
+		l.pos = start + end + 2
+		field := l.src[start+1 : start+1+end]
+		if field == "" {
+			return token{}, l.errorf(start, "path must not be empty")
+		}
+		return token{kind: tokPath, text: l.src[start:l.pos], value: field}, nil
+	}
+
+	end := scanPathLength(l.src[start:])
+	l.pos = start + end
+	text := l.src[start:l.pos]
+	if text != "$" && !strings.HasPrefix(text, "$.") && (text[0] == '$' || len(text) < 2) {
+		return token{}, l.errorf(start, "invalid path %q, paths start with $. or @", text)
+	}
+	if FieldPath(text) == "" {
+		return token{}, l.errorf(start, "path must not be empty")
+	}
+	return token{kind: tokPath, text: text, value: text}, nil
+}
+
+// scanPathLength returns how much of s belongs to an unquoted path. Paths end at whitespace,
+// punctuation or an operator; gjson queries such as `#(name=="x")` are consumed as a whole.
+func scanPathLength(s string) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		if depth > 0 {
+			switch c {
+			case '"':
+				inString = true
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			continue
+		}
+		switch {
+		case c == '(' && i > 0 && s[i-1] == '#':
+			depth++
+		case c <= ' ' || strings.IndexByte("(),[]{}:<>", c) >= 0:
+			return i
+		case c == '=' || (c == '!' && i+1 < len(s) && s[i+1] == '='):
+			return i
+		case (c == '&' || c == '|') && i+1 < len(s) && s[i+1] == c:
+			return i
+		}
+	}
+	return len(s)
+}
+
+func isDigitByte(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (l *textLexer) errorf(offset int, format string, args ...interface{}) error {
+	return newSyntaxError(l.src, offset, fmt.Sprintf(format, args...))
+}
+
+func newSyntaxError(src string, offset int, msg string) *SyntaxError {
+	if offset > len(src) {
+		offset = len(src)
+	}
+	line, col := 1, 1
+	for _, r := range src[:offset] {
+		if r == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	return &SyntaxError{Line: line, Column: col, Msg: msg}
+}
+
+// textNode is a parsed expression in the map form accepted by parseOperator.
+type textNode struct {
+	pos int
+	def map[string]interface{}
+}
+
+// compiledKey is the only key of a definition holding a compiledDef.
+const compiledKey = "\x00compiled"
+
+// compiledDef carries an operator the text parser has already compiled, so that the parent node
+// reuses it instead of compiling the child's definition again.
+type compiledDef struct {
+	op         jsonfilter.Operator
+	complexity int
+}
+
+type textParser struct {
+	parser Parser
+	lex    textLexer
+	tok    token
+}
+
+func (tp *textParser) advance() error {
+	tok, err := tp.lex.next()
+	if err != nil {
+		return err
+	}
+	tp.tok = tok
+	return nil
+}
+
+func (tp *textParser) errorf(offset int, format string, args ...interface{}) error {
+	return newSyntaxError(tp.lex.src, offset, fmt.Sprintf(format, args...))
+}
+
+func (tp *textParser) expect(kind tokenKind, what string) (token, error) {
+	tok := tp.tok
+	if tok.kind != kind {
+		return token{}, tp.errorf(tok.pos, "expected %s, found %s", what, tok)
+	}
+	return tok, tp.advance()
+}
+
+// check compiles the node so that semantic errors, such as an invalid regex, report the position of
+// the expression they belong to. The node's definition is replaced by the compiled operator, so that
+// every expression is compiled once however deeply it is nested.
+func (tp *textParser) check(node textNode) (textNode, error) {
+	op, complexity, err := tp.parser.parseOperator(node.def)
+	if err != nil {
+		return textNode{}, tp.errorf(node.pos, "%v", err)
+	}
+	node.def = map[string]interface{}{compiledKey: compiledDef{op: op, complexity: complexity}}
+	return node, nil
+}
+
+func (tp *textParser) parseOr() (textNode, error) {
+	return tp.parseChain(tokOr, string(logic.Or), tp.parseAnd)
+}
+
+func (tp *textParser) parseAnd() (textNode, error) {
+	return tp.parseChain(tokAnd, string(logic.And), tp.parseUnary)
+}
+
+func (tp *textParser) parseChain(sep tokenKind, name string, operand func() (textNode, error)) (textNode, error) {
+	first, err := operand()
+	if err != nil {
+		return textNode{}, err
+	}
+	if tp.tok.kind != sep {
+		return first, nil
+	}
+	children := []interface{}{first.def}
+	for tp.tok.kind == sep {
+		if err := tp.advance(); err != nil {
+			return textNode{}, err
+		}
+		next, err := operand()
+		if err != nil {
+			return textNode{}, err
+		}
+		children = append(children, next.def)
+	}
+	return tp.check(textNode{pos: first.pos, def: map[string]interface{}{name: children}})
+}
+
+func (tp *textParser) parseUnary() (textNode, error) {
+	if tp.tok.kind != tokNot {
+		return tp.parsePrimary()
+	}
+	pos := tp.tok.pos
+	if err := tp.advance(); err != nil {
+		return textNode{}, err
+	}
+	operand, err := tp.parseUnary()
+	if err != nil {
+		return textNode{}, err
+	}
+	return tp.check(textNode{pos: pos, def: map[string]interface{}{string(logic.Nor): []interface{}{operand.def}}})
+}
+
+func (tp *textParser) parsePrimary() (textNode, error) {
+	switch tp.tok.kind {
+	case tokLParen:
+		if err := tp.advance(); err != nil {
+			return textNode{}, err
+		}
+		node, err := tp.parseOr()
+		if err != nil {
+			return textNode{}, err
+		}
+		if _, err := tp.expect(tokRParen, "\")\""); err != nil {
+			return textNode{}, err
+		}
+		return node, nil
+	case tokPath:
+		return tp.parseInfix()
+	case tokIdent:
+		return tp.parseCall()
+	default:
+		return textNode{}, tp.errorf(tp.tok.pos, "expected a comparison, call or \"(\", found %s", tp.tok)
+	}
+}
+
+func (tp *textParser) parseInfix() (textNode, error) {
+	path := tp.tok
+	if err := tp.advance(); err != nil {
+		return textNode{}, err
+	}
+	op := tp.tok
+	if op.kind != tokCompare {
+		return textNode{}, tp.errorf(op.pos, "expected a comparison operator after %s, found %s", path, op)
+	}
+	if err := tp.advance(); err != nil {
+		return textNode{}, err
+	}
+	cfg, err := tp.parseOperand(path.value.(string))
+	if err != nil {
+		return textNode{}, err
+	}
+	return tp.check(textNode{pos: path.pos, def: map[string]interface{}{string(infixTypes[op.text]): cfg}})
+}
+
+// parseOperand reads the right-hand side of a comparison: a literal, or a path compared by reference.
+func (tp *textParser) parseOperand(field string) (map[string]interface{}, error) {
+	if tp.tok.kind == tokPath {
+		ref := tp.tok.value.(string)
+		if err := tp.advance(); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"field": field, "valueFrom": ref}, nil
+	}
+	value, err := tp.parseLiteral()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"field": field, "value": value}, nil
+}
+
+func (tp *textParser) parseLiteral() (interface{}, error) {
+	tok := tp.tok
+	switch tok.kind {
+	case tokString, tokNumber, tokRegex:
+		return tok.value, tp.advance()
+	case tokIdent:
+		var value interface{}
+		switch tok.text {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			return nil, tp.errorf(tok.pos, "expected a value, found %s", tok)
+		}
+		return value, tp.advance()
+	case tokLBracket:
+		if err := tp.advance(); err != nil {
+			return nil, err
+		}
+		list := []interface{}{}
+		for tp.tok.kind != tokRBracket {
+			item, err := tp.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+			if tp.tok.kind != tokComma {
+				break
+			}
+			if err := tp.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := tp.expect(tokRBracket, "\"]\""); err != nil {
+			return nil, err
+		}
+		return list, nil
+	case tokLBrace:
+		if err := tp.advance(); err != nil {
+			return nil, err
+		}
+		object := map[string]interface{}{}
+		for tp.tok.kind != tokRBrace {
+			key := tp.tok
+			var name string
+			switch key.kind {
+			case tokIdent:
+				name = key.text
+			case tokString:
+				name = key.value.(string)
+			default:
+				return nil, tp.errorf(key.pos, "expected an object key, found %s", key)
+			}
+			if err := tp.advance(); err != nil {
+				return nil, err
+			}
+			if _, err := tp.expect(tokColon, "\":\""); err != nil {
+				return nil, err
+			}
+			item, err := tp.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			object[name] = item
+			if tp.tok.kind != tokComma {
+				break
+			}
+			if err := tp.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := tp.expect(tokRBrace, "\"}\""); err != nil {
+			return nil, err
+		}
+		return object, nil
+	default:
+		return nil, tp.errorf(tok.pos, "expected a value, found %s", tok)
+	}
+}
+
+func (tp *textParser) parseCall() (textNode, error) {
+	fn := tp.tok
+	name := strings.ToLower(fn.text)
+	if err := tp.advance(); err != nil {
+		return textNode{}, err
+	}
+	if _, err := tp.expect(tokLParen, "\"(\" after "+fn.text); err != nil {
+		return textNode{}, err
+	}
+
+	var def map[string]interface{}
+	switch {
+	case name == "not":
+		operand, err := tp.parseOr()
+		if err != nil {
+			return textNode{}, err
+		}
+		def = map[string]interface{}{string(logic.Nor): []interface{}{operand.def}}
+	case name == string(logic.If):
+		branches, err := tp.parseExprList()
+		if err != nil {
+			return textNode{}, err
+		}
+		if len(branches) < 2 || len(branches) > 3 {
+			return textNode{}, tp.errorf(fn.pos, "if expects a condition, a then branch and an optional else branch")
+		}
+		cfg := map[string]interface{}{"if": branches[0], "then": branches[1]}
+		if len(branches) == 3 {
+			cfg["else"] = branches[2]
+		}
+		def = map[string]interface{}{name: cfg}
+	case name == nested.OperatorName:
+		path, err := tp.expect(tokPath, "a path")
+		if err != nil {
+			return textNode{}, err
+		}
+		if _, err := tp.expect(tokComma, "\",\""); err != nil {
+			return textNode{}, err
+		}
+		cfg := map[string]interface{}{"field": path.value}
+		if tp.tok.kind == tokString {
+			cfg["decode"] = tp.tok.value
+			if err := tp.advance(); err != nil {
+				return textNode{}, err
+			}
+			if _, err := tp.expect(tokComma, "\",\""); err != nil {
+				return textNode{}, err
+			}
+		}
+		filter, err := tp.parseOr()
+		if err != nil {
+			return textNode{}, err
+		}
+		cfg["filter"] = filter.def
+		def = map[string]interface{}{name: cfg}
+	case name == nested.ElemMatchName:
+		path, err := tp.expect(tokPath, "a path")
+		if err != nil {
+			return textNode{}, err
+		}
+		if _, err := tp.expect(tokComma, "\",\""); err != nil {
+			return textNode{}, err
+		}
+		filter, err := tp.parseOr()
+		if err != nil {
+			return textNode{}, err
+		}
+		def = map[string]interface{}{name: map[string]interface{}{"field": path.value, "filter": filter.def}}
+	default:
+		if typ, err := logic.ParseType(name); err == nil {
+			cfg, err := tp.parseLogicArgs(typ)
+			if err != nil {
+				return textNode{}, err
+			}
+			def = map[string]interface{}{name: cfg}
+			break
+		}
+		if _, err := comparison.ParseType(name); err == nil {
+			path, err := tp.expect(tokPath, "a path")
+			if err != nil {
+				return textNode{}, err
+			}
+			if _, err := tp.expect(tokComma, "\",\""); err != nil {
+				return textNode{}, err
+			}
+			cfg, err := tp.parseOperand(path.value.(string))
+			if err != nil {
+				return textNode{}, err
+			}
+			def = map[string]interface{}{name: cfg}
+			break
+		}
+		return textNode{}, tp.errorf(fn.pos, "unknown operator %q", fn.text)
+	}
+
+	if _, err := tp.expect(tokRParen, "\")\""); err != nil {
+		return textNode{}, err
+	}
+	return tp.check(textNode{pos: fn.pos, def: def})
+}
+
+func (tp *textParser) parseLogicArgs(typ logic.Type) (interface{}, error) {
+	if !typ.IsThreshold() {
+		return tp.parseExprList()
+	}
+	count, err := tp.expect(tokNumber, "a count")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tp.expect(tokComma, "\",\""); err != nil {
+		return nil, err
+	}
+	children, err := tp.parseExprList()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"count": count.value, "of": children}, nil
+}
+
+func (tp *textParser) parseExprList() ([]interface{}, error) {
+	var list []interface{}
+	for {
+		node, err := tp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, node.def)
+		if tp.tok.kind != tokComma {
+			return list, nil
+		}
+		if err := tp.advance(); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package serde
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromTextPrecedence(t *testing.T) {
+	op, err := DefaultParser().FromText(`$.state == "done" && ($.id =~ /^ABC/ || $.amount >= 100)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := map[string]bool{
+		`{"state":"done","id":"ABC-1","amount":1}`:   true,
+		`{"state":"done","id":"XYZ-1","amount":150}`: true,
+		`{"state":"done","id":"XYZ-1","amount":50}`:  false,
+		`{"state":"open","id":"ABC-1","amount":150}`: false,
+	}
+	for payload, want := range cases {
+		if res := op.Evaluate([]byte(payload)); res.Match != want {
+			t.Fatalf("%s: expected match=%v, got %#v", payload, want, res)
+		}
+	}
+
+	// && binds tighter than ||, ! tighter than &&.
+	loose, err := DefaultParser().FromText(`$.a == 1 || $.b == 1 && !($.c == 1)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := loose.Evaluate([]byte(`{"a":1,"b":0,"c":1}`)); !res.Match {
+		t.Fatalf("expected left operand of || to decide: %#v", res)
+	}
+	if res := loose.Evaluate([]byte(`{"a":0,"b":1,"c":1}`)); res.Match {
+		t.Fatalf("expected negated comparison to fail: %#v", res)
+	}
+}
+
+func TestFromTextMatchesYAML(t *testing.T) {
+	fromText, err := DefaultParser().FromText(
+		`atLeast(2, $.vpn == true, cidr($.ip, ["10.0.0.0/8"]), $.amount > $.limit) && if($.country == "DE", $.currency == "EUR") && size($.items, {min: 1})`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fromYAML, err := DefaultParser().FromYAML([]byte(`
+and:
+  - atLeast:
+      count: 2
+      of:
+        - eq: {field: vpn, value: true}
+        - cidr: {field: ip, value: [10.0.0.0/8]}
+        - gt: {field: amount, valueFrom: limit}
+  - if:
+      if: {eq: {field: country, value: DE}}
+      then: {eq: {field: currency, value: EUR}}
+  - size: {field: items, value: {min: 1}}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	textForm, err := ToText(fromText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	yamlForm, err := ToText(fromYAML)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if textForm != yamlForm {
+		t.Fatalf("expected identical trees:\n%s\n%s", textForm, yamlForm)
+	}
+}
+
+func TestToTextRoundTrip(t *testing.T) {
+	exprs := []string{
+		`$.state == "done" && ($.id =~ /^ABC/ || $.amount >= 100)`,
+		`($.a == 1 && $.b == 2) && $.c == 3`,
+		`!($.a == 1 || $.b != "x") && !$.c < 2.5`,
+		`xor($.card == true, $.paypal == true)`,
+		`exactly(1, $.a == 1, $.b == 1)`,
+		`if($.country == "DE", $.currency == "EUR", $.currency == "USD")`,
+		`nested($.body, "base64json", $.event == "created")`,
+		`@headers.x-tenant == "acme" && $.amount <= @env.maxAmount`,
+		"`odd key` == null && $ != {}",
+		`$.path =~ "a/b" && $.name =~ /(?i)^bob/`,
+		`between($.amount, {exclusiveMax: true, max: "10.00", min: 0}) && like($.host, "*.example.com")`,
+		`within($.ts, {from: "now-1h", to: "now"}) && format($.id, "uuid")`,
+		`and($.a == 1) || or($.b == [1, 2, "x"])`,
+		`exists($.a, false) && type($.b, ["string", "null"]) && nin($.c, [1, 2]) && ct($.d, "x")`,
+		`elemMatch($.items, $.sku == "A1" && $ >= 2)`,
+	}
+	for _, expr := range exprs {
+		op, err := DefaultParser().FromText(expr)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", expr, err)
+		}
+		printed, err := ToText(op)
+		if err != nil {
+			t.Fatalf("%s: unexpected print error: %v", expr, err)
+		}
+		if printed != expr {
+			t.Fatalf("expected round trip:\n%s\n%s", expr, printed)
+		}
+	}
+}
+
+func TestFromTextErrorPositions(t *testing.T) {
+	cases := []struct {
+		expr   string
+		line   int
+		column int
+	}{
+		{`$.a == `, 1, 8},
+		{`$.a == 1 && ($.b == 2`, 1, 22},
+		{`$.a = 1`, 1, 5},
+		{`$.a == "open`, 1, 8},
+		{"$.a == 1 &&\n  $.b =~ /[/", 2, 3},
+		{`$.a == 1 && bogus($.b, 1)`, 1, 13},
+		{`$.a == 1 # comment`, 1, 10},
+	}
+	for _, tc := range cases {
+		_, err := DefaultParser().FromText(tc.expr)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("%q: expected syntax error, got %v", tc.expr, err)
+		}
+		if syntaxErr.Line != tc.line || syntaxErr.Column != tc.column {
+			t.Fatalf("%q: expected %d:%d, got %v", tc.expr, tc.line, tc.column, err)
+		}
+	}
+}
+
+func TestFromTextCompilesEachNodeOnce(t *testing.T) {
+	// Recompiling every subtree at each level made compile time grow with the cube of the depth.
+	expr := strings.Repeat("!", 1000) + "$.a == 1"
+	start := time.Now()
+	op, err := NewParser(1 << 30).FromText(expr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("compiling %d nested expressions took %v", 1000, elapsed)
+	}
+	if res := op.Evaluate([]byte(`{"a":1}`)); !res.Match {
+		t.Fatalf("expected an even number of negations to match: %#v", res)
+	}
+
+	_, err = DefaultParser().FromText(`$.a == 1 && !($.b == 2 || $.c =~ /[/)`)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Column != 27 {
+		t.Fatalf("expected the nested error to keep its position, got %v", err)
+	}
+}